@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NursiNursi/laundry-apps/utils/common"
@@ -13,8 +14,23 @@ import (
 type ApiConfig struct {
 	ApiHost string
 	ApiPort string
+	// route tambahan yang boleh diakses tanpa token, format "METHOD /path"
+	PublicRoutes []string
+	// ip atau CIDR proxy yang header X-Forwarded-For nya dipercaya, kosong berarti ip client diambil dari koneksi langsung
+	TrustedProxies []string
+	// lama Idempotency-Key disimpan, retry setelah ini diproses sebagai request baru
+	IdempotencyKeyLifeTime time.Duration
 }
 
+// nilai DB_DRIVER yang didukung
+const (
+	DriverPostgres = "postgres"
+	// data hanya disimpan di memory dan hilang saat aplikasi berhenti, untuk development dan test
+	DriverMemory = "memory"
+	// satu file database, untuk toko dengan satu outlet tanpa server postgres
+	DriverSqlite = "sqlite"
+)
+
 type DbConfig struct {
 	Host     string
 	Port     string
@@ -22,6 +38,11 @@ type DbConfig struct {
 	User     string
 	Password string
 	Driver   string
+	// jalankan migration yang belum dijalankan saat server start
+	AutoMigrate bool
+	// akun owner yang dibuat saat server start dengan driver memory, karena datanya selalu kosong
+	MemoryOwnerUsername string
+	MemoryOwnerPassword string
 }
 
 type FileConfig struct {
@@ -29,10 +50,50 @@ type FileConfig struct {
 }
 
 type TokenConfig struct {
-	ApplicationName     string
-	JwtSignatureKey     []byte
-	JwtSigningMethod    *jwt.SigningMethodHMAC
-	AccessTokenLifeTime time.Duration
+	ApplicationName      string
+	JwtSignatureKey      []byte
+	JwtSigningMethod     *jwt.SigningMethodHMAC
+	AccessTokenLifeTime  time.Duration
+	RefreshTokenLifeTime time.Duration
+	// lama status session disimpan di cache sebelum dicek ulang ke database
+	SessionCacheLifeTime time.Duration
+}
+
+// kalender operasional toko, dipakai untuk estimasi tanggal selesai
+type BusinessConfig struct {
+	ClosedDays []time.Weekday
+	Holidays   []time.Time
+	// zona waktu toko, batas hari pada bill dan laporan mengikuti zona ini
+	Location *time.Location
+}
+
+// informasi toko yang dicetak di header dan footer nota
+type ShopConfig struct {
+	ShopName        string
+	ShopAddress     string
+	ShopPhoneNumber string
+	ReceiptFooter   string
+	// lebar kertas printer thermal dalam mm (58 atau 80)
+	ReceiptPaperWidth int
+}
+
+// aturan password untuk user baru, ganti password dan reset password
+type PasswordConfig struct {
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+}
+
+// pembatasan percobaan login yang gagal
+type LoginConfig struct {
+	// jumlah gagal yang masih dibiarkan tanpa jeda
+	LoginBackoffAfter int
+	// jeda awal, dikali dua setiap kali gagal lagi
+	LoginBackoffBase     time.Duration
+	LoginMaxAttempts     int
+	LoginMaxIpAttempts   int
+	LoginLockoutDuration time.Duration
 }
 
 type Config struct {
@@ -40,6 +101,10 @@ type Config struct {
 	DbConfig
 	FileConfig
 	TokenConfig
+	BusinessConfig
+	ShopConfig
+	PasswordConfig
+	LoginConfig
 }
 
 // Method
@@ -56,13 +121,29 @@ func (c *Config) ReadConfig() error {
 		User:     os.Getenv("DB_USER"),
 		Password: os.Getenv("DB_PASSWORD"),
 		Driver:   os.Getenv("DB_DRIVER"),
+
+		MemoryOwnerUsername: os.Getenv("MEMORY_OWNER_USERNAME"),
+		MemoryOwnerPassword: os.Getenv("MEMORY_OWNER_PASSWORD"),
+	}
+
+	if c.AutoMigrate, err = parseBool("DB_AUTO_MIGRATE"); err != nil {
+		return err
 	}
 
 	c.ApiConfig = ApiConfig{
-		ApiHost: os.Getenv("API_HOST"),
-		ApiPort: os.Getenv("API_PORT"),
+		ApiHost:      os.Getenv("API_HOST"),
+		ApiPort:      os.Getenv("API_PORT"),
+		PublicRoutes: splitList(os.Getenv("PUBLIC_ROUTES")),
+
+		TrustedProxies: splitList(os.Getenv("TRUSTED_PROXIES")),
 	}
 
+	idempotencyKeyExpire, err := parseInt("IDEMPOTENCY_KEY_EXPIRE", 24)
+	if err != nil {
+		return err
+	}
+	c.IdempotencyKeyLifeTime = time.Duration(idempotencyKeyExpire) * time.Hour
+
 	c.FileConfig = FileConfig{
 		FilePath: os.Getenv("FILE_PATH"),
 	}
@@ -73,21 +154,201 @@ func (c *Config) ReadConfig() error {
 	}
 	accessTokenLifeTime := time.Duration(appTokenExpire) * time.Minute
 
+	// default refresh token berlaku 7 hari
+	refreshTokenExpire := 7 * 24 * 60
+	if value := os.Getenv("APP_REFRESH_TOKEN_EXPIRE"); value != "" {
+		refreshTokenExpire, err = strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	sessionCacheExpire := 30
+	if value := os.Getenv("APP_SESSION_CACHE_EXPIRE"); value != "" {
+		sessionCacheExpire, err = strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.TokenConfig = TokenConfig{
-		ApplicationName:     os.Getenv("APP_TOKEN_NAME"),
-		JwtSignatureKey:     []byte(os.Getenv("APP_TOKEN_KEY")),
-		JwtSigningMethod:    jwt.SigningMethodHS256,
-		AccessTokenLifeTime: accessTokenLifeTime,
+		ApplicationName:      os.Getenv("APP_TOKEN_NAME"),
+		JwtSignatureKey:      []byte(os.Getenv("APP_TOKEN_KEY")),
+		JwtSigningMethod:     jwt.SigningMethodHS256,
+		AccessTokenLifeTime:  accessTokenLifeTime,
+		RefreshTokenLifeTime: time.Duration(refreshTokenExpire) * time.Minute,
+		SessionCacheLifeTime: time.Duration(sessionCacheExpire) * time.Second,
+	}
+
+	closedDays, err := parseWeekdays(os.Getenv("BUSINESS_CLOSED_DAYS"))
+	if err != nil {
+		return err
+	}
+	holidays, err := parseDates(os.Getenv("BUSINESS_HOLIDAYS"))
+	if err != nil {
+		return err
+	}
+
+	location := time.Local
+	if value := os.Getenv("APP_TIMEZONE"); value != "" {
+		location, err = time.LoadLocation(value)
+		if err != nil {
+			return fmt.Errorf("invalid APP_TIMEZONE %s", value)
+		}
+	}
+
+	c.BusinessConfig = BusinessConfig{
+		ClosedDays: closedDays,
+		Holidays:   holidays,
+		Location:   location,
+	}
+
+	receiptPaperWidth := 58
+	if value := os.Getenv("RECEIPT_PAPER_WIDTH"); value != "" {
+		receiptPaperWidth, err = strconv.Atoi(value)
+		if err != nil || (receiptPaperWidth != 58 && receiptPaperWidth != 80) {
+			return fmt.Errorf("RECEIPT_PAPER_WIDTH must be 58 or 80")
+		}
+	}
+
+	c.ShopConfig = ShopConfig{
+		ShopName:        os.Getenv("SHOP_NAME"),
+		ShopAddress:     os.Getenv("SHOP_ADDRESS"),
+		ShopPhoneNumber: os.Getenv("SHOP_PHONE_NUMBER"),
+		ReceiptFooter:   os.Getenv("SHOP_RECEIPT_FOOTER"),
+
+		ReceiptPaperWidth: receiptPaperWidth,
 	}
 
-	if c.DbConfig.Host == "" || c.DbConfig.Port == "" || c.DbConfig.Name == "" ||
-		c.DbConfig.User == "" || c.DbConfig.Password == "" || c.DbConfig.Driver == "" ||
-		c.ApiConfig.ApiHost == "" || c.ApiConfig.ApiPort == "" || c.FileConfig.FilePath == "" {
+	passwordMinLength := 8
+	if value := os.Getenv("PASSWORD_MIN_LENGTH"); value != "" {
+		passwordMinLength, err = strconv.Atoi(value)
+		if err != nil || passwordMinLength < 1 {
+			return fmt.Errorf("PASSWORD_MIN_LENGTH must be a positive number")
+		}
+	}
+	c.PasswordConfig = PasswordConfig{PasswordMinLength: passwordMinLength}
+	if c.PasswordRequireUpper, err = parseBool("PASSWORD_REQUIRE_UPPER"); err != nil {
+		return err
+	}
+	if c.PasswordRequireDigit, err = parseBool("PASSWORD_REQUIRE_DIGIT"); err != nil {
+		return err
+	}
+	if c.PasswordRequireSymbol, err = parseBool("PASSWORD_REQUIRE_SYMBOL"); err != nil {
+		return err
+	}
+
+	c.LoginConfig = LoginConfig{
+		LoginBackoffAfter:    3,
+		LoginBackoffBase:     time.Second,
+		LoginMaxAttempts:     10,
+		LoginMaxIpAttempts:   50,
+		LoginLockoutDuration: 15 * time.Minute,
+	}
+	if c.LoginBackoffAfter, err = parseInt("LOGIN_BACKOFF_AFTER", c.LoginBackoffAfter); err != nil {
+		return err
+	}
+	if c.LoginMaxAttempts, err = parseInt("LOGIN_MAX_ATTEMPTS", c.LoginMaxAttempts); err != nil {
+		return err
+	}
+	if c.LoginMaxIpAttempts, err = parseInt("LOGIN_MAX_IP_ATTEMPTS", c.LoginMaxIpAttempts); err != nil {
+		return err
+	}
+	lockoutMinutes, err := parseInt("LOGIN_LOCKOUT_DURATION", 15)
+	if err != nil {
+		return err
+	}
+	c.LoginLockoutDuration = time.Duration(lockoutMinutes) * time.Minute
+
+	if c.DbConfig.Driver == "" || c.ApiConfig.ApiHost == "" || c.ApiConfig.ApiPort == "" || c.FileConfig.FilePath == "" {
 		return fmt.Errorf("missing required environment variables")
 	}
+	switch c.DbConfig.Driver {
+	case DriverMemory:
+		// driver memory tidak butuh koneksi database
+	case DriverSqlite:
+		if c.DbConfig.Name == "" {
+			return fmt.Errorf("missing required environment variables")
+		}
+	default:
+		if c.DbConfig.Host == "" || c.DbConfig.Port == "" || c.DbConfig.Name == "" || c.DbConfig.User == "" || c.DbConfig.Password == "" {
+			return fmt.Errorf("missing required environment variables")
+		}
+	}
 	return nil
 }
 
+// contoh: BUSINESS_CLOSED_DAYS=sunday,saturday
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, name := range splitList(value) {
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), name) {
+				weekdays = append(weekdays, day)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid closed day %s", name)
+		}
+	}
+	if len(weekdays) >= 7 {
+		return nil, fmt.Errorf("business can not be closed every day")
+	}
+	return weekdays, nil
+}
+
+// contoh: BUSINESS_HOLIDAYS=2023-12-25,2024-01-01
+func parseDates(value string) ([]time.Time, error) {
+	var dates []time.Time
+	for _, item := range splitList(value) {
+		date, err := time.Parse("2006-01-02", item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date %s", item)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// variabel kosong memakai nilai default
+func parseInt(name string, defaultValue int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil || result < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return result, nil
+}
+
+// variabel kosong dianggap false
+func parseBool(name string) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %s", name, value)
+	}
+	return result, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // constructor
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// file migration: migrations/<version>_<nama>.up.sql dan migrations/<version>_<nama>.down.sql
+// file dengan nama yang sama di migrations/<driver>/ dipakai untuk driver tersebut, misalnya tipe kolom yang berbeda
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus berisi migration beserta waktu dijalankan, AppliedAt nil berarti belum dijalankan
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrations mengembalikan semua migration yang di embed untuk driver, urut dari version terkecil
+func Migrations(driver string) ([]Migration, error) {
+	files, err := migrationPaths(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for fileName, filePath := range files {
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+		versionText, name, found := strings.Cut(strings.TrimSuffix(fileName, "."+direction+".sql"), "_")
+		version, err := strconv.Atoi(versionText)
+		if !found || err != nil || version < 1 {
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+
+		content, err := migrationFiles.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// migrationPaths memetakan nama file migration ke path yang dipakai, file khusus driver menggantikan file umum
+func migrationPaths(driver string) (map[string]string, error) {
+	paths, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(paths))
+	for _, filePath := range paths {
+		files[path.Base(filePath)] = filePath
+	}
+
+	overrides, err := fs.Glob(migrationFiles, "migrations/"+driver+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, filePath := range overrides {
+		fileName := path.Base(filePath)
+		// file khusus driver hanya boleh mengganti, supaya version di semua database tetap sama
+		if _, ok := files[fileName]; !ok {
+			return nil, fmt.Errorf("migration %s for %s driver has no default file", fileName, driver)
+		}
+		files[fileName] = filePath
+	}
+	return files, nil
+}
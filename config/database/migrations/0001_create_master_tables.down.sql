@@ -0,0 +1,4 @@
+drop table if exists employee;
+drop table if exists product;
+drop table if exists uom;
+drop table if exists customer;
@@ -0,0 +1,46 @@
+-- database yang dibuat dari init.sql lama sudah punya tabel ini tanpa kolom tambahan,
+-- jadi tabel hanya dibuat kalau belum ada dan kolom baru ditambahkan kalau belum ada
+create table if not exists customer (
+    id varchar(100) primary key,
+    name varchar(100),
+    phone_number varchar(15) unique,
+    address text,
+    version int not null default 1,
+    deleted_at timestamp
+);
+alter table customer add column if not exists version int not null default 1;
+alter table customer add column if not exists deleted_at timestamp;
+
+create table if not exists uom (
+    id varchar(100) primary key,
+    name varchar(30) not null,
+    version int not null default 1,
+    deleted_at timestamp
+);
+alter table uom add column if not exists version int not null default 1;
+alter table uom add column if not exists deleted_at timestamp;
+
+create table if not exists product (
+    id varchar(100) primary key,
+    name varchar(50) not null,
+    price bigint,
+    uom_id varchar(100),
+    turnaround_hours int not null default 0,
+    version int not null default 1,
+    deleted_at timestamp,
+    foreign key(uom_id) references uom(id)
+);
+alter table product add column if not exists turnaround_hours int not null default 0;
+alter table product add column if not exists version int not null default 1;
+alter table product add column if not exists deleted_at timestamp;
+
+create table if not exists employee (
+    id varchar(100) primary key,
+    name varchar(100),
+    phone_number varchar(15) unique,
+    address text,
+    version int not null default 1,
+    deleted_at timestamp
+);
+alter table employee add column if not exists version int not null default 1;
+alter table employee add column if not exists deleted_at timestamp;
@@ -0,0 +1,13 @@
+-- user_credential sudah dipakai sebelum ada migration, dibuat manual tanpa role dan employee_id
+create table if not exists user_credential (
+    id varchar(100) primary key,
+    username varchar(50) not null unique,
+    password varchar(100) not null,
+    role varchar(20) not null default 'cashier',
+    is_active boolean not null default true,
+    employee_id varchar(100) unique,
+    foreign key(employee_id) references employee(id)
+);
+alter table user_credential add column if not exists role varchar(20) not null default 'cashier';
+alter table user_credential add column if not exists is_active boolean not null default true;
+alter table user_credential add column if not exists employee_id varchar(100) unique references employee(id);
@@ -0,0 +1,3 @@
+drop table if exists bill_status_history;
+drop table if exists bill_detail;
+drop table if exists bill;
@@ -0,0 +1,40 @@
+-- bill dan bill_detail dari init.sql lama diadopsi, bill lama dianggap berstatus received
+create table if not exists bill (
+    id varchar(100) primary key,
+    bill_date date,
+    entry_date timestamp,
+    finish_date timestamp,
+    employee_id varchar(100),
+    customer_id varchar(100),
+    status varchar(20) not null default 'received',
+    void_reason text,
+    voided_by varchar(100),
+    voided_at timestamp,
+    foreign key(employee_id) references employee(id),
+    foreign key(customer_id) references customer(id)
+);
+alter table bill add column if not exists status varchar(20) not null default 'received';
+alter table bill add column if not exists void_reason text;
+alter table bill add column if not exists voided_by varchar(100);
+alter table bill add column if not exists voided_at timestamp;
+-- init.sql lama menyimpan entry_date dan finish_date sebagai date, estimasi selesai dihitung sampai jam
+alter table bill alter column entry_date type timestamp;
+alter table bill alter column finish_date type timestamp;
+
+create table if not exists bill_detail (
+    id varchar(100) primary key,
+    bill_id varchar(100),
+    product_id varchar(100),
+    product_price bigint,
+    qty int
+);
+
+create table bill_status_history (
+    id varchar(100) primary key,
+    bill_id varchar(100) not null,
+    from_status varchar(20),
+    to_status varchar(20) not null,
+    changed_by varchar(100),
+    changed_at timestamp not null,
+    foreign key(bill_id) references bill(id)
+);
@@ -0,0 +1,11 @@
+create table payment (
+    id varchar(100) primary key,
+    bill_id varchar(100) not null,
+    amount bigint not null,
+    method varchar(20) not null,
+    payment_date timestamp not null,
+    received_by varchar(100),
+    reversal_of varchar(100),
+    foreign key(bill_id) references bill(id),
+    foreign key(reversal_of) references payment(id)
+);
@@ -0,0 +1,16 @@
+create table user_session (
+    id varchar(100) primary key,
+    user_id varchar(100) not null,
+    created_at timestamp not null,
+    revoked_at timestamp,
+    foreign key(user_id) references user_credential(id)
+);
+
+create table refresh_token (
+    id varchar(100) primary key,
+    session_id varchar(100) not null,
+    token_hash varchar(64) not null unique,
+    expires_at timestamp not null,
+    used_at timestamp,
+    foreign key(session_id) references user_session(id)
+);
@@ -0,0 +1,2 @@
+drop table if exists login_lockout;
+drop table if exists login_attempt;
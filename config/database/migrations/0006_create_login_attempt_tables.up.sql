@@ -0,0 +1,19 @@
+create table login_attempt (
+    kind varchar(20) not null,
+    value varchar(100) not null,
+    failed_count int not null,
+    last_failed_at timestamp not null,
+    locked_until timestamp,
+    primary key(kind, value)
+);
+
+create table login_lockout (
+    id varchar(100) primary key,
+    kind varchar(20) not null,
+    value varchar(100) not null,
+    failed_count int not null,
+    locked_at timestamp not null,
+    locked_until timestamp not null,
+    unlocked_by varchar(100),
+    unlocked_at timestamp
+);
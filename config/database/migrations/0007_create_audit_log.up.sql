@@ -0,0 +1,12 @@
+create table audit_log (
+    id varchar(100) primary key,
+    actor varchar(50) not null,
+    action varchar(30) not null,
+    entity_type varchar(30) not null,
+    entity_id varchar(100) not null,
+    before_data jsonb,
+    after_data jsonb,
+    created_at timestamp not null
+);
+
+create index audit_log_entity_idx on audit_log(entity_type, entity_id);
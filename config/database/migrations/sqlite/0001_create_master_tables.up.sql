@@ -0,0 +1,35 @@
+create table customer (
+    id varchar(100) primary key,
+    name varchar(100),
+    phone_number varchar(15) unique,
+    address text,
+    version int not null default 1,
+    deleted_at timestamp
+);
+
+create table uom (
+    id varchar(100) primary key,
+    name varchar(30) not null,
+    version int not null default 1,
+    deleted_at timestamp
+);
+
+create table product (
+    id varchar(100) primary key,
+    name varchar(50) not null,
+    price bigint,
+    uom_id varchar(100),
+    turnaround_hours int not null default 0,
+    version int not null default 1,
+    deleted_at timestamp,
+    foreign key(uom_id) references uom(id)
+);
+
+create table employee (
+    id varchar(100) primary key,
+    name varchar(100),
+    phone_number varchar(15) unique,
+    address text,
+    version int not null default 1,
+    deleted_at timestamp
+);
@@ -0,0 +1,9 @@
+create table user_credential (
+    id varchar(100) primary key,
+    username varchar(50) not null unique,
+    password varchar(100) not null,
+    role varchar(20) not null default 'cashier',
+    is_active boolean not null default true,
+    employee_id varchar(100) unique,
+    foreign key(employee_id) references employee(id)
+);
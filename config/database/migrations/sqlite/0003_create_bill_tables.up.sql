@@ -0,0 +1,32 @@
+create table bill (
+    id varchar(100) primary key,
+    bill_date date,
+    entry_date timestamp,
+    finish_date timestamp,
+    employee_id varchar(100),
+    customer_id varchar(100),
+    status varchar(20) not null default 'received',
+    void_reason text,
+    voided_by varchar(100),
+    voided_at timestamp,
+    foreign key(employee_id) references employee(id),
+    foreign key(customer_id) references customer(id)
+);
+
+create table bill_detail (
+    id varchar(100) primary key,
+    bill_id varchar(100),
+    product_id varchar(100),
+    product_price bigint,
+    qty int
+);
+
+create table bill_status_history (
+    id varchar(100) primary key,
+    bill_id varchar(100) not null,
+    from_status varchar(20),
+    to_status varchar(20) not null,
+    changed_by varchar(100),
+    changed_at timestamp not null,
+    foreign key(bill_id) references bill(id)
+);
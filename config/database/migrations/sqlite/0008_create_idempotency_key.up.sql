@@ -0,0 +1,12 @@
+create table idempotency_key (
+    username varchar(50) not null,
+    idempotency_key varchar(100) not null,
+    request_hash varchar(64) not null,
+    status_code int,
+    content_type varchar(100),
+    response_body blob,
+    created_at timestamp not null,
+    primary key(username, idempotency_key)
+);
+
+create index idempotency_key_created_at_idx on idempotency_key(created_at);
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/delivery"
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+const usage = `usage: laundry-apps <command> [arguments]
+
+commands:
+  serve                      run the HTTP server (default when no command is given)
+  migrate up|down|status
+  user create -username <username> [-password <password>] [-role owner|admin|cashier|operator] [-employee <employee id>]
+  user reset-password -username <username> [-password <password>]
+  seed [-demo]               create default UOMs and products, -demo also creates a sample employee and customer
+  export bills [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-status <status>] [-format csv|json] [-output <file>]
+  report revenue [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-groupBy day|week|month] [-format text|json]`
+
+// actor yang dicatat di audit log untuk perubahan dari command line
+const cliActor = "cli"
+
+// command line dijalankan langsung di server sehingga diperlakukan sebagai owner, misalnya untuk membuat owner pertama
+const cliRole = model.RoleOwner
+
+// Run menjalankan server atau perintah admin dari command line, contoh: laundry-apps user create -username owner
+func Run(args []string) error {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "serve":
+		delivery.NewServer().Run()
+		return nil
+	case "migrate":
+		return runMigrate(args[1:])
+	case "user":
+		return runUser(args[1:])
+	case "seed":
+		return runSeed(args[1:])
+	case "export":
+		return runExport(args[1:])
+	case "report":
+		return runReport(args[1:])
+	}
+	return fmt.Errorf("unknown command %s\n\n%s", args[0], usage)
+}
+
+func newMigrationManager() (manager.MigrationManager, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	infraManager, err := manager.NewInfraManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return manager.NewMigrationManager(infraManager), nil
+}
+
+func newUseCaseManager() (manager.UseCaseManager, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	infraManager, err := manager.NewInfraManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	repoManager := manager.NewRepoManager(infraManager)
+	return manager.NewUseCaseManager(repoManager, cfg), nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model/dto"
+)
+
+// jumlah bill yang diambil per halaman saat export
+const exportPageSize = 100
+
+func runExport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	switch args[0] {
+	case "bills":
+		return exportBills(args[1:])
+	}
+	return fmt.Errorf("unknown export command %s\n\n%s", args[0], usage)
+}
+
+// exportBills menulis semua bill sesuai filter tanggal bill ke file atau stdout
+func exportBills(args []string) error {
+	flags := flag.NewFlagSet("export bills", flag.ContinueOnError)
+	from := flags.String("from", "", "bill date from (YYYY-MM-DD)")
+	to := flags.String("to", "", "bill date to (YYYY-MM-DD)")
+	status := flags.String("status", "", "only export bills with this status")
+	format := flags.String("format", "csv", "output format, csv or json")
+	output := flags.String("output", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("format must be csv or json")
+	}
+
+	paginationParam := dto.PaginationParam{
+		Limit:     exportPageSize,
+		Status:    *status,
+		SortBy:    "billDate",
+		SortOrder: "asc",
+	}
+	var err error
+	if paginationParam.BillDateFrom, err = parseDateFlag("from", *from); err != nil {
+		return err
+	}
+	if paginationParam.BillDateTo, err = parseDateFlag("to", *to); err != nil {
+		return err
+	}
+
+	useCaseManager, err := newUseCaseManager()
+	if err != nil {
+		return err
+	}
+	billUC := useCaseManager.BillUseCase()
+
+	var bills []dto.BillResponseDto
+	for page := 1; ; page++ {
+		paginationParam.Page = page
+		pageBills, paging, err := billUC.FindAllBill(paginationParam)
+		if err != nil {
+			return err
+		}
+		bills = append(bills, pageBills...)
+		if page >= paging.TotalPages {
+			break
+		}
+	}
+
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(bills); err != nil {
+			return err
+		}
+	} else if err := writeBillsCsv(writer, bills); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Printf("%d bills exported to %s\n", len(bills), *output)
+	}
+	return nil
+}
+
+func writeBillsCsv(writer io.Writer, bills []dto.BillResponseDto) error {
+	csvWriter := csv.NewWriter(writer)
+	header := []string{"id", "bill_date", "entry_date", "finish_date", "status", "customer_id", "customer_name", "employee_id", "employee_name", "total_bill", "amount_paid", "balance_due", "payment_status", "void_reason"}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	for _, bill := range bills {
+		record := []string{
+			bill.Id,
+			bill.BillDate.Format("2006-01-02"),
+			bill.EntryDate.Format(time.RFC3339),
+			bill.FinishDate.Format(time.RFC3339),
+			bill.Status,
+			bill.Customer.Id,
+			bill.Customer.Name,
+			bill.Employee.Id,
+			bill.Employee.Name,
+			strconv.Itoa(bill.TotalBill),
+			strconv.Itoa(bill.AmountPaid),
+			strconv.Itoa(bill.BalanceDue),
+			bill.PaymentStatus,
+			bill.VoidReason,
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// tanggal kosong berarti tidak difilter
+func parseDateFlag(name string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, use format YYYY-MM-DD", name)
+	}
+	return date, nil
+}
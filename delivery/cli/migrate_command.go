@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+)
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	migrationManager, err := newMigrationManager()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrationManager.Up()
+		for _, migration := range migrations {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return nil
+	case "down":
+		migration, err := migrationManager.Down()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %d_%s\n", migration.Version, migration.Name)
+		return nil
+	case "status":
+		statuses, err := migrationManager.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %s\n\n%s", args[0], usage)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runReport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	switch args[0] {
+	case "revenue":
+		return reportRevenue(args[1:])
+	}
+	return fmt.Errorf("unknown report command %s\n\n%s", args[0], usage)
+}
+
+// reportRevenue sama dengan GET /api/v1/reports/revenue, default periode dari awal bulan sampai hari ini
+func reportRevenue(args []string) error {
+	flags := flag.NewFlagSet("report revenue", flag.ContinueOnError)
+	from := flags.String("from", "", "bill date from (YYYY-MM-DD)")
+	to := flags.String("to", "", "bill date to (YYYY-MM-DD)")
+	groupBy := flags.String("groupBy", "day", "group revenue by day, week or month")
+	format := flags.String("format", "text", "output format, text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("format must be text or json")
+	}
+
+	fromDate, err := parseDateFlag("from", *from)
+	if err != nil {
+		return err
+	}
+	toDate, err := parseDateFlag("to", *to)
+	if err != nil {
+		return err
+	}
+
+	useCaseManager, err := newUseCaseManager()
+	if err != nil {
+		return err
+	}
+	report, err := useCaseManager.ReportUseCase().GetRevenueReport(fromDate, toDate, *groupBy)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Printf("Revenue %s to %s (%s, %s)\n\n", report.From, report.To, report.GroupBy, report.TimeZone)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Period\tOrders\tRevenue\tAverage Ticket\t")
+	for _, period := range report.Periods {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t\n", period.Period, period.Orders, period.Revenue, period.AverageTicket)
+	}
+	fmt.Fprintf(writer, "Total\t%d\t%d\t%d\t\n", report.TotalOrders, report.TotalRevenue, report.AverageTicket)
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Product\tQty\tUom\tRevenue\t")
+	for _, product := range report.ByProduct {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%d\t\n", product.ProductName, product.Qty, product.UomName, product.Revenue)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+)
+
+// data awal yang umum dipakai laundry kiloan, harga bisa diubah lewat API setelah seed
+var seedUoms = []string{"Kg", "Pcs"}
+
+var seedProducts = []struct {
+	name            string
+	price           int
+	uom             string
+	turnaroundHours int
+}{
+	{"Cuci Kering Reguler", 6000, "Kg", 48},
+	{"Cuci Setrika Reguler", 8000, "Kg", 48},
+	{"Cuci Setrika Express", 15000, "Kg", 6},
+	{"Setrika Saja", 5000, "Kg", 24},
+	{"Bed Cover", 25000, "Pcs", 72},
+	{"Selimut", 15000, "Pcs", 72},
+}
+
+// runSeed mengisi master data pada database yang masih kosong
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	demo := flags.Bool("demo", false, "also create a sample employee and customer")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	useCaseManager, err := newUseCaseManager()
+	if err != nil {
+		return err
+	}
+
+	// seed hanya dijalankan sekali supaya data yang sudah diubah user tidak tertimpa atau dobel
+	uomUC := useCaseManager.UomUseCase()
+	existingUoms, err := uomUC.FindAllUom(true)
+	if err != nil {
+		return err
+	}
+	if len(existingUoms) > 0 {
+		fmt.Println("database already has master data, seed skipped")
+		return nil
+	}
+
+	uoms := make(map[string]model.Uom)
+	for _, name := range seedUoms {
+		uom := model.Uom{Id: common.GenerateID(), Name: name}
+		if err := uomUC.RegisterNewUom(uom, cliActor); err != nil {
+			return err
+		}
+		uoms[name] = uom
+	}
+	fmt.Printf("%d uoms created\n", len(seedUoms))
+
+	productUC := useCaseManager.ProductUseCase()
+	for _, seed := range seedProducts {
+		product := model.Product{
+			Id:              common.GenerateID(),
+			Name:            seed.name,
+			Price:           seed.price,
+			TurnaroundHours: seed.turnaroundHours,
+			Uom:             uoms[seed.uom],
+		}
+		if err := productUC.RegisterNewProduct(product, cliActor); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%d products created\n", len(seedProducts))
+
+	if !*demo {
+		return nil
+	}
+	employee := model.Employee{
+		Id:          common.GenerateID(),
+		Name:        "Karyawan Demo",
+		PhoneNumber: "080000000001",
+		Address:     "Jakarta",
+	}
+	if err := useCaseManager.EmployeeUseCase().RegisterNewEmployee(employee, cliActor); err != nil {
+		return err
+	}
+	customer := model.Customer{
+		Id:          common.GenerateID(),
+		Name:        "Pelanggan Demo",
+		PhoneNumber: "080000000002",
+		Address:     "Jakarta",
+	}
+	if err := useCaseManager.CustomerUseCase().RegisterNewCustomer(customer, cliActor); err != nil {
+		return err
+	}
+	fmt.Printf("sample employee %s and customer %s created\n", employee.Id, customer.Id)
+	return nil
+}
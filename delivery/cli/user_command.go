@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+)
+
+func runUser(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	switch args[0] {
+	case "create":
+		return createUser(args[1:])
+	case "reset-password":
+		return resetPassword(args[1:])
+	}
+	return fmt.Errorf("unknown user command %s\n\n%s", args[0], usage)
+}
+
+// createUser dipakai untuk membuat akun pertama (owner) tanpa lewat endpoint HTTP
+func createUser(args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the new account")
+	password := flags.String("password", "", "password of the new account, read from stdin when empty")
+	role := flags.String("role", model.RoleOwner, "role of the new account")
+	employeeId := flags.String("employee", "", "employee ID linked to the new account")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *password == "" {
+		var err error
+		*password, err = readLine("Password: ")
+		if err != nil {
+			return err
+		}
+	}
+
+	useCaseManager, err := newUseCaseManager()
+	if err != nil {
+		return err
+	}
+	user := model.UserCredential{
+		Id:       common.GenerateID(),
+		Username: *username,
+		Password: *password,
+		Role:     *role,
+
+		EmployeeId: *employeeId,
+	}
+	if err := useCaseManager.UserUseCase().RegisterNewUser(user, cliActor, cliRole); err != nil {
+		return err
+	}
+	fmt.Printf("user %s created with role %s\n", user.Username, user.Role)
+	return nil
+}
+
+// resetPassword dipakai kalau owner lupa password dan tidak ada admin lain yang bisa reset lewat API
+func resetPassword(args []string) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the account")
+	password := flags.String("password", "", "new password, read from stdin when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("username is required")
+	}
+
+	if *password == "" {
+		var err error
+		*password, err = readLine("New password: ")
+		if err != nil {
+			return err
+		}
+	}
+
+	useCaseManager, err := newUseCaseManager()
+	if err != nil {
+		return err
+	}
+	userUC := useCaseManager.UserUseCase()
+	user, err := userUC.FindByUsername(*username)
+	if err != nil {
+		return fmt.Errorf("active user with username %s not found", *username)
+	}
+	if err := userUC.ResetPassword(user.Id, *password, cliActor, cliRole); err != nil {
+		return err
+	}
+	fmt.Printf("password of user %s has been reset\n", user.Username)
+	return nil
+}
+
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	router  *gin.Engine
+	auditUC usecase.AuditUseCase
+}
+
+func (a *AuditController) listHandler(c *gin.Context) {
+	from, to, err := parseReportRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	filter := dto.AuditFilterDto{
+		Page:       page,
+		Limit:      limit,
+		EntityType: c.Query("entityType"),
+		EntityId:   c.Query("entityId"),
+		Actor:      c.Query("actor"),
+		DateFrom:   from,
+		DateTo:     to,
+	}
+
+	audits, paging, err := a.auditUC.FindAllAudit(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get All Data Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   audits,
+		"paging": paging,
+	})
+}
+
+func NewAuditController(r *gin.Engine, usecase usecase.AuditUseCase) *AuditController {
+	controller := AuditController{
+		router:  r,
+		auditUC: usecase,
+	}
+	rg := r.Group("/api/v1")
+	rg.GET("/audit", middleware.RequirePermission(security.ServiceAuditRead), controller.listHandler)
+	return &controller
+}
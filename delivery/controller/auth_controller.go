@@ -1,10 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,13 +26,69 @@ func (a *AuthController) loginHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := a.usecase.Login(payload.Username, payload.Password)
+	token, err := a.usecase.Login(payload.Username, payload.Password, c.ClientIP())
 	if err != nil {
+		var tooManyRequests *exceptions.TooManyRequestsError
+		if errors.As(err, &tooManyRequests) {
+			c.Header("Retry-After", strconv.Itoa(tooManyRequests.RetryAfterSeconds()))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"err": err.Error()})
+			return
+		}
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"token": token})
+	c.JSON(http.StatusCreated, token)
+}
+
+func (a *AuthController) refreshHandler(c *gin.Context) {
+	var payload dto.RefreshTokenRequestDto
+	if err := c.ShouldBindJSON(&payload); err != nil || payload.RefreshToken == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"err": "refreshToken is required"})
+		return
+	}
+
+	token, err := a.usecase.RefreshToken(payload.RefreshToken)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"err": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (a *AuthController) logoutHandler(c *gin.Context) {
+	if err := a.usecase.Logout(middleware.GetSessionId(c)); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+
+	c.String(http.StatusNoContent, "")
+}
+
+func (a *AuthController) listLockoutHandler(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+	lockouts, err := a.usecase.FindAllLockout(activeOnly)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get All Data Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   lockouts,
+	})
+}
+
+func (a *AuthController) unlockHandler(c *gin.Context) {
+	if err := a.usecase.UnlockLockout(c.Param("id"), middleware.GetUsername(c)); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
 }
 
 func NewAuthController(r *gin.Engine, usecase usecase.AuthUseCase) *AuthController {
@@ -36,5 +98,9 @@ func NewAuthController(r *gin.Engine, usecase usecase.AuthUseCase) *AuthControll
 	}
 	rg := r.Group("/api/v1")
 	rg.POST("/login", controller.loginHandler)
+	rg.POST("/token/refresh", controller.refreshHandler)
+	rg.POST("/logout", controller.logoutHandler)
+	rg.GET("/login-lockouts", middleware.RequirePermission(security.ServiceUserManage), controller.listLockoutHandler)
+	rg.POST("/login-lockouts/:id/unlock", middleware.RequirePermission(security.ServiceUserManage), controller.unlockHandler)
 	return &controller
 }
@@ -1,19 +1,28 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/NursiNursi/laundry-apps/utils/receipt"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
 type BillController struct {
-	router *gin.Engine
-	billUC usecase.BillUseCase
+	router  *gin.Engine
+	billUC  usecase.BillUseCase
+	shopCfg config.ShopConfig
 }
 
 func (b *BillController) createHandler(c *gin.Context) {
@@ -23,8 +32,23 @@ func (b *BillController) createHandler(c *gin.Context) {
 		return
 	}
 
+	// karyawan diambil dari user yang login, hanya owner dan admin yang boleh mengisi karyawan lain
+	employeeId := middleware.GetEmployeeId(c)
+	if bill.EmployeeId != "" && bill.EmployeeId != employeeId {
+		if !middleware.HasPermission(c, security.ServiceBillAssign) {
+			c.JSON(http.StatusForbidden, gin.H{"err": "you are not allowed to create a bill for another employee"})
+			return
+		}
+		employeeId = bill.EmployeeId
+	}
+	if employeeId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"err": "your account is not linked to an employee, employeeId is required"})
+		return
+	}
+	bill.EmployeeId = employeeId
+
 	bill.Id = common.GenerateID()
-	if err := b.billUC.RegisterNewBill(bill); err != nil {
+	if err := b.billUC.RegisterNewBill(bill, middleware.GetUsername(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
 		return
 	}
@@ -35,12 +59,38 @@ func (b *BillController) listHandler(c *gin.Context) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	limit, _ := strconv.Atoi(c.Query("limit"))
 	paginationParam := dto.PaginationParam{
-		Page:  page,
-		Limit: limit,
+		Page:       page,
+		Limit:      limit,
+		Status:     c.Query("status"),
+		CustomerId: c.Query("customerId"),
+		EmployeeId: c.Query("employeeId"),
+		SortBy:     c.Query("sortBy"),
+		SortOrder:  c.Query("sortOrder"),
+	}
+
+	// format tanggal filter: YYYY-MM-DD
+	dateFilters := map[string]*time.Time{
+		"billDateFrom":   &paginationParam.BillDateFrom,
+		"billDateTo":     &paginationParam.BillDateTo,
+		"finishDateFrom": &paginationParam.FinishDateFrom,
+		"finishDateTo":   &paginationParam.FinishDateTo,
+	}
+	for key, target := range dateFilters {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": fmt.Sprintf("invalid %s, use format YYYY-MM-DD", key)})
+			return
+		}
+		*target = date
 	}
+
 	bills, paging, err := b.billUC.FindAllBill(paginationParam)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		c.JSON(pagingErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
 	status := map[string]any{
@@ -70,15 +120,163 @@ func (b *BillController) getHandler(c *gin.Context) {
 	})
 }
 
-func NewBillController(r *gin.Engine, usecase usecase.BillUseCase) *BillController {
+func (b *BillController) updateStatusHandler(c *gin.Context) {
+	var statusRequest dto.BillStatusRequestDto
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if err := b.billUC.UpdateBillStatus(id, statusRequest.Status, middleware.GetUsername(c)); err != nil {
+		c.JSON(billErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+
+	bill, err := b.billUC.FindByIdBill(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Update Status Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   bill,
+	})
+}
+
+func (b *BillController) voidHandler(c *gin.Context) {
+	var voidRequest dto.BillVoidRequestDto
+	if err := c.ShouldBindJSON(&voidRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if err := b.billUC.VoidBill(id, voidRequest.Reason, middleware.GetUsername(c)); err != nil {
+		c.JSON(billErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+
+	bill, err := b.billUC.FindByIdBill(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Void Bill Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   bill,
+	})
+}
+
+func (b *BillController) amendHandler(c *gin.Context) {
+	var bill model.Bill
+	if err := c.ShouldBindJSON(&bill); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if err := b.billUC.AmendBill(id, bill.BillDetails, middleware.GetUsername(c)); err != nil {
+		c.JSON(billErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+
+	amendedBill, err := b.billUC.FindByIdBill(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Amend Bill Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   amendedBill,
+	})
+}
+
+func (b *BillController) receiptPdfHandler(c *gin.Context) {
+	bill, err := b.billUC.FindByIdBill(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+
+	pdf, err := receipt.GeneratePDF(bill, b.shopCfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"receipt-%s.pdf\"", bill.Id))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (b *BillController) receiptEscPosHandler(c *gin.Context) {
+	bill, err := b.billUC.FindByIdBill(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+
+	// client POS boleh override lebar kertas kalau printernya beda dengan konfigurasi
+	paperWidth := b.shopCfg.ReceiptPaperWidth
+	if value := c.Query("paperWidth"); value != "" {
+		paperWidth, _ = strconv.Atoi(value)
+	}
+	escpos, err := receipt.GenerateEscPos(bill, b.shopCfg, paperWidth)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"receipt-%s.bin\"", bill.Id))
+	c.Data(http.StatusOK, "application/octet-stream", escpos)
+}
+
+// billErrorStatus memetakan error bill ke status http, error lain dianggap kesalahan server
+func billErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, exceptions.ErrBillNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, exceptions.ErrInvalidBillRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, exceptions.ErrBillStatusConflict), errors.Is(err, exceptions.ErrBillTotalBelowAmountPaid):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// pagingErrorStatus membalas 400 kalau sortBy atau sortOrder tidak dikenal, dipakai juga oleh daftar bill customer
+func pagingErrorStatus(err error) int {
+	if errors.Is(err, exceptions.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func NewBillController(r *gin.Engine, usecase usecase.BillUseCase, shopCfg config.ShopConfig) *BillController {
 	controller := BillController{
-		router: r,
-		billUC: usecase,
+		router:  r,
+		billUC:  usecase,
+		shopCfg: shopCfg,
 	}
 
 	rg := r.Group("/api/v1")
-	rg.POST("/bills", controller.createHandler)
+	rg.POST("/bills", middleware.RequirePermission(security.ServiceBillCreate), controller.createHandler)
 	rg.GET("/bills", controller.listHandler)
 	rg.GET("/bills/:id", controller.getHandler)
+	rg.GET("/bills/:id/receipt.pdf", controller.receiptPdfHandler)
+	rg.GET("/bills/:id/receipt.escpos", controller.receiptEscPosHandler)
+	rg.PATCH("/bills/:id/status", middleware.RequirePermission(security.ServiceBillStatus), controller.updateStatusHandler)
+	rg.POST("/bills/:id/void", middleware.RequirePermission(security.ServiceBillVoid), controller.voidHandler)
+	rg.PATCH("/bills/:id/details", middleware.RequirePermission(security.ServiceBillAmend), controller.amendHandler)
 	return &controller
 }
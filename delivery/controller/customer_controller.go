@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,7 +26,7 @@ func (cc *CustomerController) createHandler(c *gin.Context) {
 	}
 
 	customer.Id = common.GenerateID()
-	if err := cc.usecase.RegisterNewCustomer(customer); err != nil {
+	if err := cc.usecase.RegisterNewCustomer(customer, middleware.GetUsername(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
 		return
 	}
@@ -34,9 +36,15 @@ func (cc *CustomerController) createHandler(c *gin.Context) {
 func (cc *CustomerController) listHandler(c *gin.Context) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	limit, _ := strconv.Atoi(c.Query("limit"))
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
 	paginationParam := dto.PaginationParam{
-		Page:  page,
-		Limit: limit,
+		Page:           page,
+		Limit:          limit,
+		IncludeDeleted: includeDeleted,
 	}
 	customers, paging, err := cc.usecase.FindAllCustomer(paginationParam)
 	if err != nil {
@@ -55,7 +63,16 @@ func (cc *CustomerController) listHandler(c *gin.Context) {
 }
 func (cc *CustomerController) getHandler(c *gin.Context) {
 	id := c.Param("id")
-	customer, err := cc.usecase.FindByIdCustomer(id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
+	find := cc.usecase.FindByIdCustomer
+	if includeDeleted {
+		find = cc.usecase.FindByIdCustomerIncludeDeleted
+	}
+	customer, err := find(id)
 	if err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
@@ -64,6 +81,7 @@ func (cc *CustomerController) getHandler(c *gin.Context) {
 		"code":        200,
 		"description": "Get By Id Data Successfully",
 	}
+	setETag(c, customer.Version)
 	c.JSON(200, gin.H{
 		"status": status,
 		"data":   customer,
@@ -75,17 +93,74 @@ func (cc *CustomerController) updateHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	customer.Version = version
 
-	if err := cc.usecase.UpdateCustomer(customer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+	updated, err := cc.usecase.UpdateCustomer(customer, middleware.GetUsername(c))
+	if err != nil {
+		c.JSON(updateErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, customer)
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
 }
 func (cc *CustomerController) deleteHandler(c *gin.Context) {
 	id := c.Param("id")
-	if err := cc.usecase.DeleteCustomer(id); err != nil {
+	if err := cc.usecase.DeleteCustomer(id, middleware.GetUsername(c)); err != nil {
+		c.JSON(500, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(204, "")
+}
+
+func (cc *CustomerController) listBillHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	paginationParam := dto.PaginationParam{
+		Page:      page,
+		Limit:     limit,
+		Status:    c.Query("status"),
+		SortBy:    c.Query("sortBy"),
+		SortOrder: c.Query("sortOrder"),
+	}
+	bills, paging, err := cc.usecase.FindCustomerBills(c.Param("id"), paginationParam)
+	if err != nil {
+		c.JSON(pagingErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get All Data Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   bills,
+		"paging": paging,
+	})
+}
+func (cc *CustomerController) summaryHandler(c *gin.Context) {
+	summary, err := cc.usecase.GetCustomerSummary(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get Summary Data Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   summary,
+	})
+}
+
+func (cc *CustomerController) restoreHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := cc.usecase.RestoreCustomer(id, middleware.GetUsername(c)); err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
 	}
@@ -98,10 +173,13 @@ func NewCustomerController(r *gin.Engine, usecase usecase.CustomerUseCase) *Cust
 		usecase: usecase,
 	}
 	rg := r.Group("/api/v1")
-	rg.POST("/customers", controller.createHandler)
+	rg.POST("/customers", middleware.RequirePermission(security.ServiceCustomerWrite), controller.createHandler)
 	rg.GET("/customers", controller.listHandler)
 	rg.GET("/customers/:id", controller.getHandler)
-	rg.PUT("/customers", controller.updateHandler)
-	rg.DELETE("/customers/:id", controller.deleteHandler)
+	rg.GET("/customers/:id/bills", controller.listBillHandler)
+	rg.GET("/customers/:id/summary", controller.summaryHandler)
+	rg.PUT("/customers", middleware.RequirePermission(security.ServiceCustomerWrite), controller.updateHandler)
+	rg.DELETE("/customers/:id", middleware.RequirePermission(security.ServiceMasterWrite), controller.deleteHandler)
+	rg.POST("/customers/:id/restore", middleware.RequirePermission(security.ServiceMasterWrite), controller.restoreHandler)
 	return &controller
 }
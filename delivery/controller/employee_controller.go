@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,7 +26,7 @@ func (e *EmployeeController) createHandler(c *gin.Context) {
 	}
 
 	employee.Id = common.GenerateID()
-	if err := e.usecase.RegisterNewEmployee(employee); err != nil {
+	if err := e.usecase.RegisterNewEmployee(employee, middleware.GetUsername(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
 		return
 	}
@@ -34,9 +36,15 @@ func (e *EmployeeController) createHandler(c *gin.Context) {
 func (e *EmployeeController) listHandler(c *gin.Context) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	limit, _ := strconv.Atoi(c.Query("limit"))
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
 	paginationParam := dto.PaginationParam{
-		Page:  page,
-		Limit: limit,
+		Page:           page,
+		Limit:          limit,
+		IncludeDeleted: includeDeleted,
 	}
 	employees, paging, err := e.usecase.FindAllEmployee(paginationParam)
 	if err != nil {
@@ -55,7 +63,16 @@ func (e *EmployeeController) listHandler(c *gin.Context) {
 }
 func (e *EmployeeController) getHandler(c *gin.Context) {
 	id := c.Param("id")
-	employee, err := e.usecase.FindByIdEmployee(id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
+	find := e.usecase.FindByIdEmployee
+	if includeDeleted {
+		find = e.usecase.FindByIdEmployeeIncludeDeleted
+	}
+	employee, err := find(id)
 	if err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
@@ -64,6 +81,7 @@ func (e *EmployeeController) getHandler(c *gin.Context) {
 		"code":        200,
 		"description": "Get By Id Data Successfully",
 	}
+	setETag(c, employee.Version)
 	c.JSON(200, gin.H{
 		"status": status,
 		"data":   employee,
@@ -75,17 +93,33 @@ func (e *EmployeeController) updateHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	employee.Version = version
 
-	if err := e.usecase.UpdateEmployee(employee); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+	updated, err := e.usecase.UpdateEmployee(employee, middleware.GetUsername(c))
+	if err != nil {
+		c.JSON(updateErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, employee)
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
 }
 func (e *EmployeeController) deleteHandler(c *gin.Context) {
 	id := c.Param("id")
-	if err := e.usecase.DeleteEmployee(id); err != nil {
+	if err := e.usecase.DeleteEmployee(id, middleware.GetUsername(c)); err != nil {
+		c.JSON(500, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(204, "")
+}
+
+func (e *EmployeeController) restoreHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := e.usecase.RestoreEmployee(id, middleware.GetUsername(c)); err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
 	}
@@ -98,10 +132,11 @@ func NewEmployeeController(r *gin.Engine, usecase usecase.EmployeeUseCase) *Empl
 		usecase: usecase,
 	}
 	rg := r.Group("/api/v1")
-	rg.POST("/employees", controller.createHandler)
+	rg.POST("/employees", middleware.RequirePermission(security.ServiceMasterWrite), controller.createHandler)
 	rg.GET("/employees", controller.listHandler)
 	rg.GET("/employees/:id", controller.getHandler)
-	rg.PUT("/employees", controller.updateHandler)
-	rg.DELETE("/employees/:id", controller.deleteHandler)
+	rg.PUT("/employees", middleware.RequirePermission(security.ServiceMasterWrite), controller.updateHandler)
+	rg.DELETE("/employees/:id", middleware.RequirePermission(security.ServiceMasterWrite), controller.deleteHandler)
+	rg.POST("/employees/:id/restore", middleware.RequirePermission(security.ServiceMasterWrite), controller.restoreHandler)
 	return &controller
 }
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/gin-gonic/gin"
+)
+
+// ETag berisi version baris, dikirim balik oleh client lewat header If-Match saat PUT
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseIfMatch langsung membalas 428 kalau header If-Match tidak dikirim
+func parseIfMatch(c *gin.Context) (int, bool) {
+	value := c.GetHeader("If-Match")
+	if value == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"err": "If-Match header is required, use the ETag from GET"})
+		return 0, false
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"err": exceptions.ErrVersionConflict.Error()})
+		return 0, false
+	}
+	return version, true
+}
+
+func updateErrorStatus(err error) int {
+	if errors.Is(err, exceptions.ErrVersionConflict) {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
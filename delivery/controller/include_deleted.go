@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/gin-gonic/gin"
+)
+
+// parseIncludeDeleted membaca query includeDeleted=true, hanya boleh dipakai role yang punya permission deleted:read
+func parseIncludeDeleted(c *gin.Context) (bool, error) {
+	if c.Query("includeDeleted") != "true" {
+		return false, nil
+	}
+	if !middleware.HasPermission(c, security.ServiceDeletedRead) {
+		return false, fmt.Errorf("you are not allowed to view deleted data")
+	}
+	return true, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController struct {
+	router    *gin.Engine
+	paymentUC usecase.PaymentUseCase
+}
+
+func (p *PaymentController) createHandler(c *gin.Context) {
+	var paymentRequest dto.PaymentRequestDto
+	if err := c.ShouldBindJSON(&paymentRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	payment := model.Payment{
+		BillId:     c.Param("id"),
+		Amount:     paymentRequest.Amount,
+		Method:     paymentRequest.Method,
+		ReceivedBy: middleware.GetUsername(c),
+	}
+	payment, err := p.paymentUC.RegisterNewPayment(payment)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+func (p *PaymentController) listHandler(c *gin.Context) {
+	payments, err := p.paymentUC.FindAllPaymentByBill(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get All Data Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   payments,
+	})
+}
+
+// paymentErrorStatus membalas 409 kalau nominal melebihi sisa tagihan, termasuk yang kalah balapan dengan pembayaran lain
+func paymentErrorStatus(err error) int {
+	if errors.Is(err, exceptions.ErrPaymentExceedsBalance) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func NewPaymentController(r *gin.Engine, usecase usecase.PaymentUseCase) *PaymentController {
+	controller := PaymentController{
+		router:    r,
+		paymentUC: usecase,
+	}
+
+	rg := r.Group("/api/v1")
+	rg.POST("/bills/:id/payments", middleware.RequirePermission(security.ServicePaymentCreate), controller.createHandler)
+	rg.GET("/bills/:id/payments", controller.listHandler)
+	return &controller
+}
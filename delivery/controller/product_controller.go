@@ -4,9 +4,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,7 +29,8 @@ func (p *ProductController) createHandler(c *gin.Context) {
 	newProduct.Name = productRequest.Name
 	newProduct.Uom.Id = productRequest.UomId
 	newProduct.Price = productRequest.Price
-	if err := p.productUC.RegisterNewProduct(newProduct); err != nil {
+	newProduct.TurnaroundHours = productRequest.TurnaroundHours
+	if err := p.productUC.RegisterNewProduct(newProduct, middleware.GetUsername(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
 		return
 	}
@@ -37,9 +40,15 @@ func (p *ProductController) createHandler(c *gin.Context) {
 func (p *ProductController) listHandler(c *gin.Context) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	limit, _ := strconv.Atoi(c.Query("limit"))
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
 	paginationParam := dto.PaginationParam{
-		Page:  page,
-		Limit: limit,
+		Page:           page,
+		Limit:          limit,
+		IncludeDeleted: includeDeleted,
 	}
 	products, paging, err := p.productUC.FindAllProduct(paginationParam)
 	if err != nil {
@@ -59,7 +68,16 @@ func (p *ProductController) listHandler(c *gin.Context) {
 
 func (p *ProductController) getHandler(c *gin.Context) {
 	id := c.Param("id")
-	product, err := p.productUC.FindByIdProduct(id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"err": err.Error()})
+		return
+	}
+	find := p.productUC.FindByIdProduct
+	if includeDeleted {
+		find = p.productUC.FindByIdProductIncludeDeleted
+	}
+	product, err := find(id)
 	if err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
@@ -68,6 +86,7 @@ func (p *ProductController) getHandler(c *gin.Context) {
 		"code":        200,
 		"description": "Get By Id Data Successfully",
 	}
+	setETag(c, product.Version)
 	c.JSON(200, gin.H{
 		"status": status,
 		"data":   product,
@@ -79,27 +98,44 @@ func (p *ProductController) updateHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 	var newProduct model.Product
 	newProduct.Id = productRequest.Id
 	newProduct.Name = productRequest.Name
 	newProduct.Uom.Id = productRequest.UomId
 	newProduct.Price = productRequest.Price
-	if err := p.productUC.UpdateProduct(newProduct); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+	newProduct.TurnaroundHours = productRequest.TurnaroundHours
+	newProduct.Version = version
+	updated, err := p.productUC.UpdateProduct(newProduct, middleware.GetUsername(c))
+	if err != nil {
+		c.JSON(updateErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
+	setETag(c, updated.Version)
 
-	c.JSON(http.StatusOK, productRequest)
+	c.JSON(http.StatusOK, updated)
 }
 func (p *ProductController) deleteHandler(c *gin.Context) {
 	id := c.Param("id")
-	if err := p.productUC.DeleteProduct(id); err != nil {
+	if err := p.productUC.DeleteProduct(id, middleware.GetUsername(c)); err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
 	}
 	c.String(204, "Product Deleted")
 }
 
+func (p *ProductController) restoreHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := p.productUC.RestoreProduct(id, middleware.GetUsername(c)); err != nil {
+		c.JSON(500, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(204, "")
+}
+
 func NewProductController(r *gin.Engine, usecase usecase.ProductUseCase) *ProductController {
 	controller := ProductController{
 		router:    r,
@@ -107,10 +143,11 @@ func NewProductController(r *gin.Engine, usecase usecase.ProductUseCase) *Produc
 	}
 	// /api/v1/products
 	rg := r.Group("/api/v1")
-	rg.POST("/products", controller.createHandler)
+	rg.POST("/products", middleware.RequirePermission(security.ServiceMasterWrite), controller.createHandler)
 	rg.GET("/products", controller.listHandler)
 	rg.GET("/products/:id", controller.getHandler)
-	rg.PUT("/products", controller.updateHandler)
-	rg.DELETE("/products/:id", controller.deleteHandler)
+	rg.PUT("/products", middleware.RequirePermission(security.ServiceMasterWrite), controller.updateHandler)
+	rg.DELETE("/products/:id", middleware.RequirePermission(security.ServiceProductDelete), controller.deleteHandler)
+	rg.POST("/products/:id/restore", middleware.RequirePermission(security.ServiceProductDelete), controller.restoreHandler)
 	return &controller
 }
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	router   *gin.Engine
+	reportUC usecase.ReportUseCase
+}
+
+func (r *ReportController) revenueHandler(c *gin.Context) {
+	from, to, err := parseReportRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	report, err := r.reportUC.GetRevenueReport(from, to, c.Query("groupBy"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get Revenue Report Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   report,
+	})
+}
+
+func (r *ReportController) employeeHandler(c *gin.Context) {
+	from, to, err := parseReportRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	report, err := r.reportUC.GetEmployeeReport(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	status := map[string]any{
+		"code":        200,
+		"description": "Get Employee Report Successfully",
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"data":   report,
+	})
+}
+
+// parseReportRange membaca query from & to dengan format YYYY-MM-DD, sudah dianggap tanggal lokal toko
+func parseReportRange(c *gin.Context) (time.Time, time.Time, error) {
+	var from, to time.Time
+	for key, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s, use format YYYY-MM-DD", key)
+		}
+		*target = date
+	}
+	return from, to, nil
+}
+
+func NewReportController(r *gin.Engine, usecase usecase.ReportUseCase) *ReportController {
+	controller := ReportController{
+		router:   r,
+		reportUC: usecase,
+	}
+
+	rg := r.Group("/api/v1")
+	rg.GET("/reports/revenue", middleware.RequirePermission(security.ServiceReportRead), controller.revenueHandler)
+	rg.GET("/reports/employees", middleware.RequirePermission(security.ServiceReportRead), controller.employeeHandler)
+	return &controller
+}
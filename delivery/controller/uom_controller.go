@@ -4,6 +4,7 @@ import (
 	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,7 +23,7 @@ func (u *UomController) createHandler(c *gin.Context) {
 	}
 	// cek error ketikan server tidak merespon atau ada kesalahan, keluarkan status code 500 (internal server error - SERVER)
 	// uom.Id = common.GenerateID()
-	if err := u.uomUC.RegisterNewUom(uom); err != nil {
+	if err := u.uomUC.RegisterNewUom(uom, middleware.GetUsername(c)); err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return // ini harus ada supaya gak diteruskan ke bawah
 	}
@@ -31,7 +32,12 @@ func (u *UomController) createHandler(c *gin.Context) {
 }
 
 func (u *UomController) listHandler(c *gin.Context) {
-	uoms, err := u.uomUC.FindAllUom()
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(403, gin.H{"err": err.Error()})
+		return
+	}
+	uoms, err := u.uomUC.FindAllUom(includeDeleted)
 	if err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
@@ -50,7 +56,16 @@ func (u *UomController) listHandler(c *gin.Context) {
 
 func (u *UomController) getHandler(c *gin.Context) {
 	id := c.Param("id")
-	uom, err := u.uomUC.FindByIdUom(id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(403, gin.H{"err": err.Error()})
+		return
+	}
+	find := u.uomUC.FindByIdUom
+	if includeDeleted {
+		find = u.uomUC.FindByIdUomIncludeDeleted
+	}
+	uom, err := find(id)
 	if err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
@@ -59,6 +74,7 @@ func (u *UomController) getHandler(c *gin.Context) {
 		"code":        200,
 		"description": "Get By Id Data Successfully",
 	}
+	setETag(c, uom.Version)
 	c.JSON(200, gin.H{
 		"status": status,
 		"data":   uom,
@@ -71,16 +87,32 @@ func (u *UomController) updateHandler(c *gin.Context) {
 		c.JSON(400, gin.H{"err": err.Error()})
 		return
 	}
-	if err := u.uomUC.UpdateUom(uom); err != nil {
-		c.JSON(500, gin.H{"err": err.Error()})
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	uom.Version = version
+	updated, err := u.uomUC.UpdateUom(uom, middleware.GetUsername(c))
+	if err != nil {
+		c.JSON(updateErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
-	c.JSON(200, uom)
+	setETag(c, updated.Version)
+	c.JSON(200, updated)
 }
 
 func (u *UomController) deleteHandler(c *gin.Context) {
 	id := c.Param("id")
-	if err := u.uomUC.DeleteUom(id); err != nil {
+	if err := u.uomUC.DeleteUom(id, middleware.GetUsername(c)); err != nil {
+		c.JSON(500, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(204, "")
+}
+
+func (u *UomController) restoreHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := u.uomUC.RestoreUom(id, middleware.GetUsername(c)); err != nil {
 		c.JSON(500, gin.H{"err": err.Error()})
 		return
 	}
@@ -96,10 +128,11 @@ func NewUomController(usecase usecase.UomUseCase, r *gin.Engine) *UomController
 	// /uom -> GET, POST, PUT, DELETE
 	// /api/v1/uoms
 	rg := r.Group("/api/v1")
-	rg.POST("/uoms", middleware.AuthMiddleware(), controller.createHandler)
-	rg.GET("/uoms", middleware.AuthMiddleware(), controller.listHandler)
-	rg.GET("/uoms/:id", middleware.AuthMiddleware(), controller.getHandler)
-	rg.PUT("/uoms", middleware.AuthMiddleware(), controller.updateHandler)
-	rg.DELETE("/uoms/:id", middleware.AuthMiddleware(), controller.deleteHandler)
+	rg.POST("/uoms", middleware.RequirePermission(security.ServiceMasterWrite), controller.createHandler)
+	rg.GET("/uoms", controller.listHandler)
+	rg.GET("/uoms/:id", controller.getHandler)
+	rg.PUT("/uoms", middleware.RequirePermission(security.ServiceMasterWrite), controller.updateHandler)
+	rg.DELETE("/uoms/:id", middleware.RequirePermission(security.ServiceMasterWrite), controller.deleteHandler)
+	rg.POST("/uoms/:id/restore", middleware.RequirePermission(security.ServiceMasterWrite), controller.restoreHandler)
 	return &controller
 }
@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/usecase"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,15 +27,17 @@ func (u *UserController) createHandler(c *gin.Context) {
 	}
 
 	user.Id = common.GenerateID()
-	if err := u.userUC.RegisterNewUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+	if err := u.userUC.RegisterNewUser(user, middleware.GetUsername(c), middleware.GetRole(c)); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"err": err.Error()})
 		return
 	}
 
 	userResponse := map[string]any{
-		"id":       user.Id,
-		"username": user.Username,
-		"isActive": user.IsActive,
+		"id":         user.Id,
+		"username":   user.Username,
+		"role":       user.Role,
+		"isActive":   user.IsActive,
+		"employeeId": user.EmployeeId,
 	}
 
 	c.JSON(http.StatusOK, userResponse)
@@ -52,6 +59,78 @@ func (u *UserController) listHandler(c *gin.Context) {
 	})
 }
 
+func (u *UserController) changePasswordHandler(c *gin.Context) {
+	var payload dto.ChangePasswordRequestDto
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	if err := u.userUC.ChangePassword(middleware.GetUsername(c), payload.OldPassword, payload.NewPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
+}
+
+func (u *UserController) resetPasswordHandler(c *gin.Context) {
+	var payload dto.ResetPasswordRequestDto
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	if err := u.userUC.ResetPassword(c.Param("id"), payload.NewPassword, middleware.GetUsername(c), middleware.GetRole(c)); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
+}
+
+func (u *UserController) updateActiveHandler(c *gin.Context) {
+	var payload dto.UserActiveRequestDto
+	if err := c.ShouldBindJSON(&payload); err != nil || payload.IsActive == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": "isActive is required field"})
+		return
+	}
+
+	if err := u.userUC.UpdateActiveUser(c.Param("id"), *payload.IsActive, middleware.GetUsername(c), middleware.GetRole(c)); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
+}
+
+func (u *UserController) updateEmployeeHandler(c *gin.Context) {
+	var payload dto.UserEmployeeRequestDto
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	if err := u.userUC.UpdateEmployeeUser(c.Param("id"), payload.EmployeeId, middleware.GetUsername(c), middleware.GetRole(c)); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
+}
+
+func (u *UserController) deleteHandler(c *gin.Context) {
+	if err := u.userUC.DeleteUser(c.Param("id"), middleware.GetUsername(c), middleware.GetRole(c)); err != nil {
+		c.JSON(userErrorStatus(err), gin.H{"err": err.Error()})
+		return
+	}
+	c.String(http.StatusNoContent, "")
+}
+
+// userErrorStatus membalas 403 untuk aksi yang ditolak karena role user yang login
+func userErrorStatus(err error) int {
+	if errors.Is(err, exceptions.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
 func NewUserController(r *gin.Engine, usecase usecase.UserUseCase) *UserController {
 	controller := UserController{
 		router: r,
@@ -59,7 +138,12 @@ func NewUserController(r *gin.Engine, usecase usecase.UserUseCase) *UserControll
 	}
 
 	rg := r.Group("/api/v1")
-	rg.POST("/users", controller.createHandler)
-	rg.GET("/users", controller.listHandler)
+	rg.POST("/users", middleware.RequirePermission(security.ServiceUserManage), controller.createHandler)
+	rg.GET("/users", middleware.RequirePermission(security.ServiceUserManage), controller.listHandler)
+	rg.PUT("/users/me/password", controller.changePasswordHandler)
+	rg.PUT("/users/:id/password", middleware.RequirePermission(security.ServiceUserManage), controller.resetPasswordHandler)
+	rg.PATCH("/users/:id/active", middleware.RequirePermission(security.ServiceUserManage), controller.updateActiveHandler)
+	rg.PUT("/users/:id/employee", middleware.RequirePermission(security.ServiceUserManage), controller.updateEmployeeHandler)
+	rg.DELETE("/users/:id", middleware.RequirePermission(security.ServiceUserManage), controller.deleteHandler)
 	return &controller
 }
@@ -1,20 +1,32 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/NursiNursi/laundry-apps/usecase"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type authHeader struct {
 	AuthorizationHeader string `header:"Authorization"`
 }
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware dipasang global di engine, route di publicRoutes (format "METHOD /path") dilewati
+func AuthMiddleware(authUC usecase.AuthUseCase, publicRoutes []string) gin.HandlerFunc {
+	public := make(map[string]bool, len(publicRoutes))
+	for _, route := range publicRoutes {
+		public[route] = true
+	}
+
 	return func(c *gin.Context) {
+		// route yang tidak terdaftar dibiarkan supaya tetap dapat 404
+		if c.FullPath() == "" || public[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
 		var h authHeader
 		if err := c.ShouldBindHeader(&h); err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
@@ -30,14 +42,53 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := security.VerifyAccessToken(tokenHeader)
+		claims, err := authUC.VerifyAccessToken(tokenHeader)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
 			c.Abort()
 			return
 		}
-		fmt.Println("claims:", claims["username"])
 		c.Set("claims", claims)
 		c.Next()
 	}
 }
+
+// GetRole mengambil role dari claims yang di set oleh AuthMiddleware
+func GetRole(c *gin.Context) string {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	role, _ := claims.(jwt.MapClaims)["role"].(string)
+	return role
+}
+
+// GetUsername mengambil username dari claims yang di set oleh AuthMiddleware
+func GetUsername(c *gin.Context) string {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	username, _ := claims.(jwt.MapClaims)["username"].(string)
+	return username
+}
+
+// GetSessionId mengambil id session dari claims yang di set oleh AuthMiddleware
+func GetSessionId(c *gin.Context) string {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	sessionId, _ := claims.(jwt.MapClaims)["sid"].(string)
+	return sessionId
+}
+
+// GetEmployeeId mengambil id karyawan yang terhubung dengan user yang login
+func GetEmployeeId(c *gin.Context) string {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	employeeId, _ := claims.(jwt.MapClaims)["employeeId"].(string)
+	return employeeId
+}
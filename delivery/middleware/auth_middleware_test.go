@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	ucm := newUseCaseManager(t)
+	authUC := ucm.AuthUseCase()
+	mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-ani", Username: "ani", Password: "Rahasia123", Role: model.RoleOwner}, "system", model.RoleOwner))
+	mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-budi", Username: "budi", Password: "Rahasia123", Role: model.RoleCashier}, "system", model.RoleOwner))
+	owner, err := authUC.Login("ani", "Rahasia123", "10.0.0.7")
+	mustNoErr(t, err)
+	cashier, err := authUC.Login("budi", "Rahasia123", "10.0.0.7")
+	mustNoErr(t, err)
+	loggedOut, err := authUC.Login("ani", "Rahasia123", "10.0.0.7")
+	mustNoErr(t, err)
+	claims, err := authUC.VerifyAccessToken(loggedOut.Token)
+	mustNoErr(t, err)
+	mustNoErr(t, authUC.Logout(claims["sid"].(string)))
+
+	engine := gin.New()
+	engine.Use(middleware.AuthMiddleware(authUC, []string{"GET /public"}))
+	engine.GET("/public", func(c *gin.Context) { c.String(http.StatusOK, "public") })
+	engine.GET("/private", func(c *gin.Context) { c.String(http.StatusOK, middleware.GetUsername(c)) })
+	engine.GET("/deleted", middleware.RequirePermission(security.ServiceDeletedRead), func(c *gin.Context) { c.String(http.StatusOK, "deleted") })
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		wantCode int
+		wantBody string
+	}{
+		{name: "public route without token", path: "/public", wantCode: http.StatusOK, wantBody: "public"},
+		{name: "unknown route", path: "/missing", wantCode: http.StatusNotFound},
+		{name: "missing token", path: "/private", wantCode: http.StatusUnauthorized},
+		{name: "invalid token", path: "/private", token: "bukan-token", wantCode: http.StatusUnauthorized},
+		{name: "valid token", path: "/private", token: owner.Token, wantCode: http.StatusOK, wantBody: "ani"},
+		{name: "revoked session", path: "/private", token: loggedOut.Token, wantCode: http.StatusUnauthorized},
+		{name: "permission granted", path: "/deleted", token: owner.Token, wantCode: http.StatusOK, wantBody: "deleted"},
+		{name: "permission denied", path: "/deleted", token: cashier.Token, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
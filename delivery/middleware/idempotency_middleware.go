@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/gin-gonic/gin"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// responseRecorder menyalin body response supaya bisa disimpan untuk retry
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware dipasang global setelah AuthMiddleware. Request POST dari user yang login dengan header
+// Idempotency-Key hanya diproses sekali, retry dengan key dan body yang sama mendapat response yang pertama.
+func IdempotencyMiddleware(idempotencyUC usecase.IdempotencyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		username := GetUsername(c)
+		// route publik (login, refresh token) tidak disimpan karena response nya berisi token
+		if c.Request.Method != http.MethodPost || key == "" || username == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 100 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"err": "Idempotency-Key must be at most 100 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// key yang sama untuk path lain juga dianggap request yang berbeda
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		stored, started, err := idempotencyUC.Begin(username, key, requestHash)
+		if err != nil {
+			if errors.Is(err, exceptions.ErrIdempotencyKeyReused) {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"err": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+			return
+		}
+		if !started {
+			if stored.StatusCode == 0 {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"err": "a request with this idempotency key is still being processed"})
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// key dilepas kalau response tidak berhasil disimpan, termasuk saat handler panic dan gin.Recovery
+		// melewati kode setelah c.Next(), supaya retry tidak tertahan 409 sampai key kadaluarsa
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := idempotencyUC.Abort(username, key); err != nil {
+				log.Printf("failed to release idempotency key %s: %v", key, err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Next()
+
+		// error server tidak disimpan supaya request boleh diulang dengan key yang sama
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+		if err := idempotencyUC.Complete(username, key, c.Writer.Status(), c.Writer.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("failed to save idempotent response %s: %v", key, err)
+			return
+		}
+		completed = true
+	}
+}
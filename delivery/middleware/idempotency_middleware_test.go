@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/delivery/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newIdempotencyEngine memasang claims langsung sebagai pengganti AuthMiddleware
+func newIdempotencyEngine(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	engine := gin.New()
+	engine.Use(gin.RecoveryWithWriter(io.Discard))
+	engine.Use(func(c *gin.Context) {
+		if username := c.GetHeader("X-Test-User"); username != "" {
+			c.Set("claims", jwt.MapClaims{"username": username})
+		}
+		c.Next()
+	})
+	engine.Use(middleware.IdempotencyMiddleware(newUseCaseManager(t).IdempotencyUseCase()))
+	engine.POST("/bills", handler)
+	engine.GET("/bills", handler)
+	return engine
+}
+
+func sendRequest(engine *gin.Engine, method string, username string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/bills", strings.NewReader(body))
+	if username != "" {
+		req.Header.Set("X-Test-User", username)
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyMiddlewareReplay(t *testing.T) {
+	var calls int32
+	engine := newIdempotencyEngine(t, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"call": atomic.AddInt32(&calls, 1)})
+	})
+
+	first := sendRequest(engine, http.MethodPost, "ani", "key-1", `{"qty":3}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", first.Code)
+	}
+	retry := sendRequest(engine, http.MethodPost, "ani", "key-1", `{"qty":3}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry should replay the first response, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("replay should keep content type, got %q", retry.Header().Get("Content-Type"))
+	}
+	if reused := sendRequest(engine, http.MethodPost, "ani", "key-1", `{"qty":4}`); reused.Code != http.StatusUnprocessableEntity {
+		t.Errorf("key reused with another body should get 422, got %d", reused.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("handler should run once, ran %d times", calls)
+	}
+
+	// key milik user lain, request tanpa key, GET dan request tanpa login tidak ikut disimpan
+	for _, rec := range []*httptest.ResponseRecorder{
+		sendRequest(engine, http.MethodPost, "budi", "key-1", `{"qty":4}`),
+		sendRequest(engine, http.MethodPost, "ani", "", `{"qty":3}`),
+		sendRequest(engine, http.MethodGet, "ani", "key-1", ""),
+		sendRequest(engine, http.MethodPost, "", "key-1", `{"qty":4}`),
+	} {
+		if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+			t.Errorf("request should reach the handler, got %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	if calls != 5 {
+		t.Errorf("expected 5 handler calls, got %d", calls)
+	}
+
+	if rec := sendRequest(engine, http.MethodPost, "ani", strings.Repeat("k", 101), `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("long key should get 400, got %d", rec.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareInFlight(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	engine := newIdempotencyEngine(t, func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"id": "b1"})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- sendRequest(engine, http.MethodPost, "ani", "key-1", `{}`) }()
+	<-entered
+	if rec := sendRequest(engine, http.MethodPost, "ani", "key-1", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("request with a key still in flight should get 409, got %d", rec.Code)
+	}
+	close(release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Errorf("first request should finish with 201, got %d", rec.Code)
+	}
+	if rec := sendRequest(engine, http.MethodPost, "ani", "key-1", `{}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry after completion should be replayed, got %d", rec.Code)
+	}
+}
+
+// response 5xx dan handler yang panic tidak disimpan, key dilepas supaya retry diproses ulang
+func TestIdempotencyMiddlewareReleasesKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure gin.HandlerFunc
+	}{
+		{name: "server error", failure: func(c *gin.Context) { c.JSON(http.StatusInternalServerError, gin.H{"err": "db down"}) }},
+		{name: "panic", failure: func(c *gin.Context) { panic("handler panic") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			engine := newIdempotencyEngine(t, func(c *gin.Context) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					tt.failure(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{"id": "b1"})
+			})
+
+			if rec := sendRequest(engine, http.MethodPost, "ani", "key-1", `{}`); rec.Code != http.StatusInternalServerError {
+				t.Fatalf("expected 500, got %d", rec.Code)
+			}
+			rec := sendRequest(engine, http.MethodPost, "ani", "key-1", `{}`)
+			if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" || calls != 2 {
+				t.Errorf("retry should be processed again, got %d after %d calls", rec.Code, calls)
+			}
+		})
+	}
+}
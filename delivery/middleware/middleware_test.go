@@ -0,0 +1,61 @@
+package middleware_test
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/gin-gonic/gin"
+)
+
+// isi .env untuk test, token dibuat dan diverifikasi dengan config yang dibaca dari working directory
+const testEnv = `DB_DRIVER=memory
+API_HOST=localhost
+API_PORT=8080
+FILE_PATH=laundry.log
+APP_TOKEN_NAME=laundry-test
+APP_TOKEN_KEY=laundry-test-key
+APP_TOKEN_EXPIRE=15
+DEFAULT_ROWS_PER_PAGE=10
+`
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	dir, err := os.MkdirTemp("", "laundry-apps-test")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(testEnv), 0o600); err != nil {
+		log.Fatalln(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		log.Fatalln(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newUseCaseManager memakai repository memory, setiap test mendapat store yang kosong
+func newUseCaseManager(t *testing.T) manager.UseCaseManager {
+	t.Helper()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	infra, err := manager.NewInfraManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager.NewUseCaseManager(manager.NewRepoManager(infra), cfg)
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
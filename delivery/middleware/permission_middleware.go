@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequirePermission harus dipasang setelah AuthMiddleware, mengecek service yang ada di claims token
+func RequirePermission(service string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Get("claims")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		if HasPermission(c, service) {
+			c.Next()
+			return
+		}
+
+		role, _ := claims.(jwt.MapClaims)["role"].(string)
+		c.JSON(http.StatusForbidden, gin.H{"message": fmt.Sprintf("Forbidden: role %q does not have permission %s", role, service)})
+		c.Abort()
+	}
+}
+
+// HasPermission dipakai handler yang perlu cek permission tambahan di tengah proses
+func HasPermission(c *gin.Context, service string) bool {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return false
+	}
+	services, _ := claims.(jwt.MapClaims)["services"].([]any)
+	for _, item := range services {
+		if item == service {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,15 @@ package delivery
 
 import (
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/NursiNursi/laundry-apps/config"
 	"github.com/NursiNursi/laundry-apps/delivery/controller"
 	"github.com/NursiNursi/laundry-apps/delivery/middleware"
 	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/common"
 	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -17,40 +21,84 @@ type Server struct {
 	engine     *gin.Engine
 	host       string
 	log        *logrus.Logger
+	cfg        *config.Config
 }
 
 func (s *Server) Run() {
 	s.setupControllers()
+	go s.cleanupIdempotencyKeys()
 	err := s.engine.Run(s.host)
 	if err != nil {
 		panic(err)
 	}
 }
 
+const idempotencyCleanupInterval = time.Hour
+
+// route yang boleh diakses tanpa token, route lain di /api/v1 wajib login
+var publicRoutes = []string{
+	"POST /api/v1/login",
+	"POST /api/v1/token/refresh",
+}
+
 func (s *Server) setupControllers() {
 	s.engine.Use(middleware.LogRequestMiddleware(s.log))
+	s.engine.Use(middleware.AuthMiddleware(s.useCaseManager.AuthUseCase(), append(publicRoutes, s.cfg.PublicRoutes...)))
+	s.engine.Use(middleware.IdempotencyMiddleware(s.useCaseManager.IdempotencyUseCase()))
 	// semua controller disini
 	controller.NewUomController(s.useCaseManager.UomUseCase(), s.engine)
 	controller.NewProductController(s.engine, s.useCaseManager.ProductUseCase())
 	controller.NewCustomerController(s.engine, s.useCaseManager.CustomerUseCase())
 	controller.NewEmployeeController(s.engine, s.useCaseManager.EmployeeUseCase())
-	controller.NewBillController(s.engine, s.useCaseManager.BillUseCase())
+	controller.NewBillController(s.engine, s.useCaseManager.BillUseCase(), s.cfg.ShopConfig)
+	controller.NewPaymentController(s.engine, s.useCaseManager.PaymentUseCase())
+	controller.NewReportController(s.engine, s.useCaseManager.ReportUseCase())
+	controller.NewAuditController(s.engine, s.useCaseManager.AuditUseCase())
 	controller.NewUserController(s.engine, s.useCaseManager.UserUseCase())
 	controller.NewAuthController(s.engine, s.useCaseManager.AuthUseCase())
 }
 
+// idempotency key kadaluarsa dihapus berkala, bukan di setiap request POST
+func (s *Server) cleanupIdempotencyKeys() {
+	ticker := time.NewTicker(idempotencyCleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.useCaseManager.IdempotencyUseCase().DeleteExpired(); err != nil {
+			s.log.Errorf("failed to clean up idempotency keys: %v", err)
+		}
+	}
+}
+
 func NewServer() *Server {
 	cfg, err := config.NewConfig()
 	exceptions.CheckErr(err)
 	infraManager, _ := manager.NewInfraManager(cfg)
+	if cfg.AutoMigrate && cfg.Driver != config.DriverMemory {
+		migrations, err := manager.NewMigrationManager(infraManager).Up()
+		exceptions.CheckErr(err)
+		for _, migration := range migrations {
+			log.Printf("migration %d_%s applied", migration.Version, migration.Name)
+		}
+	}
 	repoManager := manager.NewRepoManager(infraManager)
-	useCaseManager := manager.NewUseCaseManager(repoManager)
+	useCaseManager := manager.NewUseCaseManager(repoManager, cfg)
+	if cfg.Driver == config.DriverMemory && cfg.MemoryOwnerUsername != "" {
+		exceptions.CheckErr(useCaseManager.UserUseCase().RegisterNewUser(model.UserCredential{
+			Id:       common.GenerateID(),
+			Username: cfg.MemoryOwnerUsername,
+			Password: cfg.MemoryOwnerPassword,
+			Role:     model.RoleOwner,
+		}, "system", model.RoleOwner))
+	}
 	engine := gin.Default()
+	// tanpa daftar ini gin mempercayai X-Forwarded-For dari siapa saja, sehingga lockout per ip bisa dihindari
+	exceptions.CheckErr(engine.SetTrustedProxies(cfg.TrustedProxies))
 	host := fmt.Sprintf("%s:%s", cfg.ApiHost, cfg.ApiPort)
 	return &Server{
 		useCaseManager: useCaseManager,
 		engine:     engine,
 		host:       host,
 		log:        logrus.New(),
+		cfg:        cfg,
 	}
 }
@@ -1,7 +1,13 @@
 package main
 
-import "github.com/NursiNursi/laundry-apps/delivery"
+import (
+	"os"
+
+	"github.com/NursiNursi/laundry-apps/delivery/cli"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
 
 func main() {
-	delivery.NewServer().Run()
+	// tanpa argumen jalan sebagai server HTTP (sama dengan "serve"), dengan argumen jalan sebagai perintah admin
+	exceptions.CheckErr(cli.Run(os.Args[1:]))
 }
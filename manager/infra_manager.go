@@ -10,6 +10,7 @@ import (
 
 type InfraManager interface {
 	Conn() *sql.DB
+	Driver() string
 }
 
 type infraManager struct {
@@ -18,6 +19,15 @@ type infraManager struct {
 }
 
 func (i *infraManager) initDb() error {
+	switch i.cfg.Driver {
+	case config.DriverMemory:
+		// driver memory tidak membuka koneksi, Conn akan mengembalikan nil
+		return nil
+	case config.DriverSqlite:
+		// untuk sqlite DB_NAME berisi path file database
+		i.db = sql.OpenDB(newSqliteConnector(i.cfg.Name))
+		return nil
+	}
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", i.cfg.Host, i.cfg.Port, i.cfg.User, i.cfg.Password, i.cfg.Name)
 	db, err := sql.Open(i.cfg.Driver, dsn)
 	if err != nil {
@@ -31,6 +41,10 @@ func (i *infraManager) Conn() *sql.DB {
 	return i.db
 }
 
+func (i *infraManager) Driver() string {
+	return i.cfg.Driver
+}
+
 func NewInfraManager(cfg *config.Config) (InfraManager, error) {
 	conn := &infraManager{
 		cfg: cfg,
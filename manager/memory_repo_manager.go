@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/repository/memory"
+)
+
+// memoryRepoManager dipakai untuk DB_DRIVER=memory, semua repository berbagi satu store
+type memoryRepoManager struct {
+	store *memory.Store
+}
+
+// UserRepo implements RepoManager.
+func (m *memoryRepoManager) UserRepo() repository.UserRepository {
+	return memory.NewUserRepository(m.store)
+}
+
+// BillRepo implements RepoManager.
+func (m *memoryRepoManager) BillRepo() repository.BillRepository {
+	return memory.NewBillRepository(m.store)
+}
+
+// CustomerRepo implements RepoManager.
+func (m *memoryRepoManager) CustomerRepo() repository.CustomerRepository {
+	return memory.NewCustomerRepository(m.store)
+}
+
+// EmployeeRepo implements RepoManager.
+func (m *memoryRepoManager) EmployeeRepo() repository.EmployeeRepository {
+	return memory.NewEmployeeRepository(m.store)
+}
+
+// ProductRepo implements RepoManager.
+func (m *memoryRepoManager) ProductRepo() repository.ProductRepository {
+	return memory.NewProductRepository(m.store)
+}
+
+// PaymentRepo implements RepoManager.
+func (m *memoryRepoManager) PaymentRepo() repository.PaymentRepository {
+	return memory.NewPaymentRepository(m.store)
+}
+
+// ReportRepo implements RepoManager.
+func (m *memoryRepoManager) ReportRepo() repository.ReportRepository {
+	return memory.NewReportRepository(m.store)
+}
+
+// SessionRepo implements RepoManager.
+func (m *memoryRepoManager) SessionRepo() repository.SessionRepository {
+	return memory.NewSessionRepository(m.store)
+}
+
+// LoginAttemptRepo implements RepoManager.
+func (m *memoryRepoManager) LoginAttemptRepo() repository.LoginAttemptRepository {
+	return memory.NewLoginAttemptRepository(m.store)
+}
+
+// AuditRepo implements RepoManager.
+func (m *memoryRepoManager) AuditRepo() repository.AuditRepository {
+	return memory.NewAuditRepository(m.store)
+}
+
+// IdempotencyRepo implements RepoManager.
+func (m *memoryRepoManager) IdempotencyRepo() repository.IdempotencyRepository {
+	return memory.NewIdempotencyRepository(m.store)
+}
+
+// UomRepo implements RepoManager.
+func (m *memoryRepoManager) UomRepo() repository.UomRepository {
+	return memory.NewUomRepository(m.store)
+}
+
+func newMemoryRepoManager() RepoManager {
+	return &memoryRepoManager{store: memory.NewStore()}
+}
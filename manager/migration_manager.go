@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/config/database"
+)
+
+type MigrationManager interface {
+	Up() ([]database.Migration, error)
+	Down() (database.Migration, error)
+	Status() ([]database.MigrationStatus, error)
+}
+
+type migrationManager struct {
+	infra InfraManager
+}
+
+// Up implements MigrationManager.
+// menjalankan semua migration yang belum tercatat di schema_migrations, masing-masing dalam satu transaksi
+func (m *migrationManager) Up() ([]database.Migration, error) {
+	migrations, applied, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var executed []database.Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.exec(migration.Up, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)", migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return executed, fmt.Errorf("failed to apply migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		executed = append(executed, migration)
+	}
+	return executed, nil
+}
+
+// Down implements MigrationManager.
+// membatalkan satu migration terakhir yang sudah dijalankan
+func (m *migrationManager) Down() (database.Migration, error) {
+	migrations, applied, err := m.load()
+	if err != nil {
+		return database.Migration{}, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.exec(migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		if err != nil {
+			return database.Migration{}, fmt.Errorf("failed to rollback migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+	return database.Migration{}, fmt.Errorf("no migration to rollback")
+}
+
+// Status implements MigrationManager.
+func (m *migrationManager) Status() ([]database.MigrationStatus, error) {
+	migrations, applied, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]database.MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := database.MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// load membaca migration yang di embed dan version yang sudah dijalankan di database
+func (m *migrationManager) load() ([]database.Migration, map[int]time.Time, error) {
+	if m.infra.Driver() == config.DriverMemory {
+		return nil, nil, fmt.Errorf("migrations are not available for the %s driver", config.DriverMemory)
+	}
+	migrations, err := database.Migrations(m.infra.Driver())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db := m.infra.Conn()
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version int primary key,
+		name varchar(100) not null,
+		applied_at timestamp not null
+	)`)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return migrations, applied, nil
+}
+
+// script migration dan pencatatan version di jalankan dalam transaksi yang sama
+func (m *migrationManager) exec(script string, record string, args ...any) error {
+	tx, err := m.infra.Conn().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func NewMigrationManager(infra InfraManager) MigrationManager {
+	return &migrationManager{infra: infra}
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/config/database"
+)
+
+func TestSqliteMigrationUpDown(t *testing.T) {
+	cfg := &config.Config{DbConfig: config.DbConfig{Driver: config.DriverSqlite, Name: filepath.Join(t.TempDir(), "laundry.db")}}
+	infra, err := NewInfraManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer infra.Conn().Close()
+
+	migrations, err := database.Migrations(config.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrationManager := NewMigrationManager(infra)
+
+	applied, err := migrationManager.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("expected %d migrations applied, got %d", len(migrations), len(applied))
+	}
+	// migration yang sudah dijalankan tidak dijalankan ulang
+	applied, err = migrationManager.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no pending migration, got %d", len(applied))
+	}
+
+	statuses, err := migrationManager.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("migration %d_%s should be applied", status.Version, status.Name)
+		}
+	}
+
+	// semua migration bisa di rollback satu per satu sampai schema kosong, lalu dijalankan lagi
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration, err := migrationManager.Down()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if migration.Version != migrations[i].Version {
+			t.Errorf("expected rollback of version %d, got %d", migrations[i].Version, migration.Version)
+		}
+	}
+	if _, err := migrationManager.Down(); err == nil {
+		t.Error("expected error when there is no migration to rollback")
+	}
+	var tables int
+	err = infra.Conn().QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name <> 'schema_migrations'").Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("expected all tables dropped, found %d", tables)
+	}
+	if _, err := migrationManager.Up(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrationsUseDriverOverride(t *testing.T) {
+	postgres, err := database.Migrations(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := database.Migrations(config.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("drivers should share the same versions, got %d and %d", len(postgres), len(sqlite))
+	}
+	for i := range postgres {
+		if postgres[i].Version != sqlite[i].Version || postgres[i].Name != sqlite[i].Name {
+			t.Errorf("migration %d differs: %d_%s and %d_%s", i, postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+	// tipe jsonb dan bytea hanya ada di postgres
+	for _, migration := range sqlite {
+		if strings.Contains(migration.Up, "jsonb") || strings.Contains(migration.Up, "bytea") {
+			t.Errorf("migration %d_%s for sqlite uses postgres only type", migration.Version, migration.Name)
+		}
+	}
+}
+
+// database dari init.sql lama sudah punya tabel ini, migration postgres harus memakainya ulang
+func TestPostgresMigrationsAdoptLegacyTables(t *testing.T) {
+	migrations, err := database.Migrations(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var up string
+	for _, migration := range migrations {
+		up += migration.Up
+	}
+	for _, table := range []string{"customer", "uom", "product", "employee", "user_credential", "bill", "bill_detail"} {
+		if !strings.Contains(up, "create table if not exists "+table+" (") {
+			t.Errorf("migration should not fail when legacy table %s already exists", table)
+		}
+	}
+	// sqlite tidak mendukung add column if not exists dan tidak punya database lama
+	sqlite, err := database.Migrations(config.DriverSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range sqlite {
+		if strings.Contains(migration.Up, "add column if not exists") {
+			t.Errorf("migration %d_%s for sqlite uses postgres only syntax", migration.Version, migration.Name)
+		}
+	}
+}
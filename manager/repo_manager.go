@@ -1,6 +1,9 @@
 package manager
 
-import "github.com/NursiNursi/laundry-apps/repository"
+import (
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
 
 type RepoManager interface {
 	// semua repo di daftarkan disini
@@ -10,6 +13,12 @@ type RepoManager interface {
 	EmployeeRepo() repository.EmployeeRepository
 	BillRepo() repository.BillRepository
 	UserRepo() repository.UserRepository
+	PaymentRepo() repository.PaymentRepository
+	ReportRepo() repository.ReportRepository
+	SessionRepo() repository.SessionRepository
+	LoginAttemptRepo() repository.LoginAttemptRepository
+	AuditRepo() repository.AuditRepository
+	IdempotencyRepo() repository.IdempotencyRepository
 }
 
 type repoManager struct {
@@ -41,11 +50,44 @@ func (r *repoManager) ProductRepo() repository.ProductRepository {
 	return repository.NewProductRepository(r.infra.Conn())
 }
 
+// PaymentRepo implements RepoManager.
+func (r *repoManager) PaymentRepo() repository.PaymentRepository {
+	return repository.NewPaymentRepository(r.infra.Conn())
+}
+
+// ReportRepo implements RepoManager.
+func (r *repoManager) ReportRepo() repository.ReportRepository {
+	return repository.NewReportRepository(r.infra.Conn())
+}
+
+// SessionRepo implements RepoManager.
+func (r *repoManager) SessionRepo() repository.SessionRepository {
+	return repository.NewSessionRepository(r.infra.Conn())
+}
+
+// LoginAttemptRepo implements RepoManager.
+func (r *repoManager) LoginAttemptRepo() repository.LoginAttemptRepository {
+	return repository.NewLoginAttemptRepository(r.infra.Conn())
+}
+
+// AuditRepo implements RepoManager.
+func (r *repoManager) AuditRepo() repository.AuditRepository {
+	return repository.NewAuditRepository(r.infra.Conn())
+}
+
+// IdempotencyRepo implements RepoManager.
+func (r *repoManager) IdempotencyRepo() repository.IdempotencyRepository {
+	return repository.NewIdempotencyRepository(r.infra.Conn())
+}
+
 // UomRepo implements RepoManager.
 func (r *repoManager) UomRepo() repository.UomRepository {
 	return repository.NewUomRepository(r.infra.Conn())
 }
 
 func NewRepoManager(infra InfraManager) RepoManager {
+	if infra.Driver() == config.DriverMemory {
+		return newMemoryRepoManager()
+	}
 	return &repoManager{infra: infra}
 }
@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// waktu disimpan sebagai teks tanpa zona waktu seperti kolom timestamp di postgres,
+// supaya perbandingan dan pengurutan teks di sqlite tetap sama hasilnya
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999"
+
+// foreign key di sqlite harus diaktifkan per koneksi, transaksi langsung mengambil lock tulis
+// supaya dua transaksi yang bersamaan menunggu busy_timeout, bukan langsung gagal
+const sqliteParams = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+// sqliteConnector membuka koneksi modernc.org/sqlite yang dibungkus sqliteConn
+type sqliteConnector struct {
+	dsn    string
+	driver sqlite.Driver
+}
+
+func newSqliteConnector(path string) *sqliteConnector {
+	return &sqliteConnector{dsn: fmt.Sprintf("%s?%s", path, sqliteParams)}
+}
+
+// Connect implements driver.Connector.
+func (s *sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := s.driver.Open(s.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{Conn: conn}, nil
+}
+
+// Driver implements driver.Connector.
+func (s *sqliteConnector) Driver() driver.Driver {
+	return &s.driver
+}
+
+// sqliteConn meneruskan semua pemanggilan ke koneksi sqlite, hanya parameter time.Time yang diubah ke sqliteTimeFormat
+type sqliteConn struct {
+	driver.Conn
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (s *sqliteConn) CheckNamedValue(namedValue *driver.NamedValue) error {
+	value, err := driver.DefaultParameterConverter.ConvertValue(namedValue.Value)
+	if err != nil {
+		return err
+	}
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(sqliteTimeFormat)
+	}
+	namedValue.Value = value
+	return nil
+}
+
+// ExecContext implements driver.ExecerContext.
+func (s *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return s.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+// QueryContext implements driver.QueryerContext.
+func (s *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return s.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (s *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return s.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (s *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return s.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// Ping implements driver.Pinger.
+func (s *sqliteConn) Ping(ctx context.Context) error {
+	return s.Conn.(driver.Pinger).Ping(ctx)
+}
@@ -1,6 +1,10 @@
 package manager
 
-import "github.com/NursiNursi/laundry-apps/usecase"
+import (
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/usecase"
+	"github.com/NursiNursi/laundry-apps/utils/security"
+)
 
 type UseCaseManager interface {
 	UomUseCase() usecase.UomUseCase
@@ -10,48 +14,79 @@ type UseCaseManager interface {
 	BillUseCase() usecase.BillUseCase
 	UserUseCase() usecase.UserUseCase
 	AuthUseCase() usecase.AuthUseCase
+	PaymentUseCase() usecase.PaymentUseCase
+	ReportUseCase() usecase.ReportUseCase
+	AuditUseCase() usecase.AuditUseCase
+	IdempotencyUseCase() usecase.IdempotencyUseCase
 }
 
 type useCaseManager struct {
-	repoManager RepoManager
+	repoManager  RepoManager
+	cfg          *config.Config
+	// dibagi ke semua AuthUseCase supaya revoke langsung terlihat di request berikutnya
+	sessionCache *security.SessionCache
 }
 
 // AuthUseCase implements UseCaseManager.
 func (u *useCaseManager) AuthUseCase() usecase.AuthUseCase {
-	return usecase.NewAuthUseCase(u.UserUseCase())
+	return usecase.NewAuthUseCase(u.UserUseCase(), u.repoManager.SessionRepo(), u.sessionCache, u.cfg.TokenConfig, u.repoManager.LoginAttemptRepo(), u.cfg.LoginConfig)
 }
 
 
 // UserUseCase implements UseCaseManager.
 func (u *useCaseManager) UserUseCase() usecase.UserUseCase {
-	return usecase.NewUserUseCase(u.repoManager.UserRepo())
+	return usecase.NewUserUseCase(u.repoManager.UserRepo(), u.repoManager.SessionRepo(), u.sessionCache, u.cfg.PasswordConfig, u.EmployeeUseCase(), u.AuditUseCase())
 }
 
 // BillUseCase implements UseCaseManager.
 func (u *useCaseManager) BillUseCase() usecase.BillUseCase {
-	return usecase.NewBillUseCase(u.repoManager.BillRepo(), u.EmployeeUseCase(), u.CustomerUseCase(), u.ProductUseCase())
+	return usecase.NewBillUseCase(u.repoManager.BillRepo(), u.EmployeeUseCase(), u.CustomerUseCase(), u.ProductUseCase(), u.cfg.BusinessConfig, u.AuditUseCase())
 }
 
 // CustomerUseCase implements UseCaseManager.
 func (u *useCaseManager) CustomerUseCase() usecase.CustomerUseCase {
-	return usecase.NewCustomerUseCase(u.repoManager.CustomerRepo())
+	return usecase.NewCustomerUseCase(u.repoManager.CustomerRepo(), u.repoManager.BillRepo(), u.AuditUseCase())
 }
 
 // EmployeeUseCase implements UseCaseManager.
 func (u *useCaseManager) EmployeeUseCase() usecase.EmployeeUseCase {
-	return usecase.NewEmployeeUseCase(u.repoManager.EmployeeRepo())
+	return usecase.NewEmployeeUseCase(u.repoManager.EmployeeRepo(), u.AuditUseCase())
 }
 
 // ProductUseCase implements UseCaseManager.
 func (u *useCaseManager) ProductUseCase() usecase.ProductUseCase {
-	return usecase.NewProductUseCase(u.repoManager.ProductRepo(), u.UomUseCase())
+	return usecase.NewProductUseCase(u.repoManager.ProductRepo(), u.UomUseCase(), u.AuditUseCase())
+}
+
+// PaymentUseCase implements UseCaseManager.
+func (u *useCaseManager) PaymentUseCase() usecase.PaymentUseCase {
+	return usecase.NewPaymentUseCase(u.repoManager.PaymentRepo(), u.BillUseCase())
+}
+
+// ReportUseCase implements UseCaseManager.
+func (u *useCaseManager) ReportUseCase() usecase.ReportUseCase {
+	return usecase.NewReportUseCase(u.repoManager.ReportRepo(), u.repoManager.EmployeeRepo(), u.cfg.BusinessConfig)
+}
+
+// AuditUseCase implements UseCaseManager.
+func (u *useCaseManager) AuditUseCase() usecase.AuditUseCase {
+	return usecase.NewAuditUseCase(u.repoManager.AuditRepo(), u.cfg.BusinessConfig)
+}
+
+// IdempotencyUseCase implements UseCaseManager.
+func (u *useCaseManager) IdempotencyUseCase() usecase.IdempotencyUseCase {
+	return usecase.NewIdempotencyUseCase(u.repoManager.IdempotencyRepo(), u.cfg.ApiConfig)
 }
 
 // UomUseCase implements UseCaseManager.
 func (u *useCaseManager) UomUseCase() usecase.UomUseCase {
-	return usecase.NewUomUseCase(u.repoManager.UomRepo())
+	return usecase.NewUomUseCase(u.repoManager.UomRepo(), u.AuditUseCase())
 }
 
-func NewUseCaseManager(repoManager RepoManager) UseCaseManager {
-	return &useCaseManager{repoManager: repoManager}
+func NewUseCaseManager(repoManager RepoManager, cfg *config.Config) UseCaseManager {
+	return &useCaseManager{
+		repoManager:  repoManager,
+		cfg:          cfg,
+		sessionCache: security.NewSessionCache(cfg.SessionCacheLifeTime),
+	}
 }
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionRestore = "restore"
+	// aksi khusus bill dan user
+	AuditActionStatus         = "status"
+	AuditActionVoid           = "void"
+	AuditActionAmend          = "amend"
+	AuditActionChangePassword = "change_password"
+	AuditActionResetPassword  = "reset_password"
+)
+
+const (
+	AuditEntityCustomer = "customer"
+	AuditEntityEmployee = "employee"
+	AuditEntityProduct  = "product"
+	AuditEntityUom      = "uom"
+	AuditEntityBill     = "bill"
+	AuditEntityUser     = "user"
+)
+
+type AuditLog struct {
+	Id         string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityId   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
@@ -2,6 +2,18 @@ package model
 
 import "time"
 
+// status pesanan laundry, urutan sesuai proses di counter
+const (
+	BillStatusReceived = "received"
+	BillStatusWashing  = "washing"
+	BillStatusDrying   = "drying"
+	BillStatusIroning  = "ironing"
+	BillStatusReady    = "ready"
+	BillStatusPickedUp = "picked_up"
+	// khusus untuk bill yang di void, tidak bisa lewat perubahan status biasa
+	BillStatusCancelled = "cancelled"
+)
+
 type Bill struct {
 	Id string
 	BillDate time.Time
@@ -9,6 +21,7 @@ type Bill struct {
 	FinishDate time.Time
 	EmployeeId string
 	CustomerId string
+	Status string
 	BillDetails []BillDetail
 }
 
@@ -19,3 +32,20 @@ type BillDetail struct {
 	ProductPrice int
 	Qty int
 }
+
+type BillStatusHistory struct {
+	Id         string    `json:"id"`
+	BillId     string    `json:"billId"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ChangedBy  string    `json:"changedBy"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+type BillVoid struct {
+	BillId     string
+	FromStatus string
+	Reason     string
+	VoidedBy   string
+	VoidedAt   time.Time
+}
@@ -1,8 +1,12 @@
 package model
 
+import "time"
+
 type Customer struct {
 	Id string
 	Name string
 	PhoneNumber string
 	Address string
+	Version int
+	DeletedAt *time.Time `json:",omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// tanggal kosong (zero) berarti tidak difilter
+type AuditFilterDto struct {
+	Page       int
+	Limit      int
+	EntityType string
+	EntityId   string
+	Actor      string
+	DateFrom   time.Time
+	DateTo     time.Time
+}
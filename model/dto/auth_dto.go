@@ -0,0 +1,10 @@
+package dto
+
+type AuthResponseDto struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type RefreshTokenRequestDto struct {
+	RefreshToken string `json:"refreshToken"`
+}
@@ -11,10 +11,23 @@ type BillResponseDto struct {
 	BillDate    time.Time               `json:"billDate"`
 	EntryDate   time.Time               `json:"entryDate"`
 	FinishDate  time.Time               `json:"finishDate"`
+	Status      string                  `json:"status"`
 	Employee    model.Employee          `json:"employee"`
 	Customer    model.Customer          `json:"customer"`
 	BillDetails []BillDetailResponseDto `json:"billDetails"`
 	TotalBill   int                     `json:"totalBill"`
+
+	AmountPaid    int                       `json:"amountPaid"`
+	BalanceDue    int                       `json:"balanceDue"`
+	PaymentStatus string                    `json:"paymentStatus"`
+	VoidReason    string                    `json:"voidReason,omitempty"`
+	VoidedBy      string                    `json:"voidedBy,omitempty"`
+	VoidedAt      *time.Time                `json:"voidedAt,omitempty"`
+	StatusHistory []model.BillStatusHistory `json:"statusHistory,omitempty"`
+}
+
+type BillStatusRequestDto struct {
+	Status string `json:"status"`
 }
 
 type BillDetailResponseDto struct {
@@ -24,3 +37,7 @@ type BillDetailResponseDto struct {
 	ProductPrice int           `json:"productPrice"`
 	Qty          int           `json:"qty"`
 }
+
+type BillVoidRequestDto struct {
+	Reason string `json:"reason"`
+}
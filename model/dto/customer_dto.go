@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+type CustomerSummaryDto struct {
+	Customer          model.Customer        `json:"customer"`
+	TotalOrders       int                   `json:"totalOrders"`
+	TotalSpent        int                   `json:"totalSpent"`
+	AverageOrderValue int                   `json:"averageOrderValue"`
+	LastVisit         *time.Time            `json:"lastVisit"`
+	FavouriteProducts []FavouriteProductDto `json:"favouriteProducts"`
+}
+
+type FavouriteProductDto struct {
+	Product    model.Product `json:"product"`
+	TotalQty   int           `json:"totalQty"`
+	OrderCount int           `json:"orderCount"`
+}
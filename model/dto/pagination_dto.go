@@ -1,10 +1,24 @@
 package dto
 
+import "time"
+
 // untuk disimpan di parameter
 type PaginationParam struct {
 	Page int
 	Offset int
 	Limit int
+	// data yang sudah di soft delete ikut ditampilkan, hanya untuk owner dan admin
+	IncludeDeleted bool
+	// filter khusus listing bill, tanggal kosong (zero) berarti tidak difilter
+	Status         string
+	BillDateFrom   time.Time
+	BillDateTo     time.Time
+	FinishDateFrom time.Time
+	FinishDateTo   time.Time
+	CustomerId     string
+	EmployeeId     string
+	SortBy         string
+	SortOrder      string
 }
 
 // untuk disimpan di return
@@ -0,0 +1,6 @@
+package dto
+
+type PaymentRequestDto struct {
+	Amount int    `json:"amount"`
+	Method string `json:"method"`
+}
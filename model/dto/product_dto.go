@@ -1,8 +1,9 @@
 package dto
 
 type ProductRequestDto struct {
-	Id    string `json:"id"`
-	Name  string `json:"name"`
-	Price int    `json:"price"`
-	UomId string `json:"uomId"`
+	Id              string `json:"id"`
+	Name            string `json:"name"`
+	Price           int    `json:"price"`
+	UomId           string `json:"uomId"`
+	TurnaroundHours int    `json:"turnaroundHours"`
 }
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+// baris mentah hasil agregasi harian dari database
+type RevenueRowDto struct {
+	BillDate    time.Time
+	ProductId   string
+	ProductName string
+	UomId       string
+	UomName     string
+	Qty         int
+	Revenue     int
+}
+
+type OrderCountRowDto struct {
+	BillDate time.Time
+	Orders   int
+}
+
+type RevenueReportDto struct {
+	From          string                `json:"from"`
+	To            string                `json:"to"`
+	GroupBy       string                `json:"groupBy"`
+	TimeZone      string                `json:"timeZone"`
+	TotalRevenue  int                   `json:"totalRevenue"`
+	TotalOrders   int                   `json:"totalOrders"`
+	AverageTicket int                   `json:"averageTicket"`
+	ByProduct     []RevenueByProductDto `json:"byProduct"`
+	ByUom         []RevenueByUomDto     `json:"byUom"`
+	Periods       []RevenuePeriodDto    `json:"periods"`
+}
+
+type RevenuePeriodDto struct {
+	Period        string                `json:"period"`
+	Revenue       int                   `json:"revenue"`
+	Orders        int                   `json:"orders"`
+	AverageTicket int                   `json:"averageTicket"`
+	ByProduct     []RevenueByProductDto `json:"byProduct"`
+	ByUom         []RevenueByUomDto     `json:"byUom"`
+}
+
+type RevenueByProductDto struct {
+	ProductId   string `json:"productId"`
+	ProductName string `json:"productName"`
+	UomName     string `json:"uomName"`
+	Qty         int    `json:"qty"`
+	Revenue     int    `json:"revenue"`
+}
+
+type RevenueByUomDto struct {
+	UomId   string `json:"uomId"`
+	UomName string `json:"uomName"`
+	Qty     int    `json:"qty"`
+	Revenue int    `json:"revenue"`
+}
+
+// satu baris per bill untuk laporan karyawan
+type EmployeeBillRowDto struct {
+	EmployeeId string
+	EntryDate  time.Time
+	FinishDate time.Time
+	Total      int
+	ReadyAt    *time.Time
+}
+
+type EmployeeReportDto struct {
+	From      string                `json:"from"`
+	To        string                `json:"to"`
+	Employees []EmployeeWorkloadDto `json:"employees"`
+}
+
+type EmployeeWorkloadDto struct {
+	Employee               model.Employee `json:"employee"`
+	BillsHandled           int            `json:"billsHandled"`
+	RevenueHandled         int            `json:"revenueHandled"`
+	AverageTurnaroundHours float64        `json:"averageTurnaroundHours"`
+	FinishedBills          int            `json:"finishedBills"`
+	LateFinishes           int            `json:"lateFinishes"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type ChangePasswordRequestDto struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+type ResetPasswordRequestDto struct {
+	NewPassword string `json:"newPassword"`
+}
+
+type UserActiveRequestDto struct {
+	IsActive *bool `json:"isActive"`
+}
+
+type UserEmployeeRequestDto struct {
+	EmployeeId string `json:"employeeId"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// IdempotencyKey menyimpan response pertama dari request POST supaya retry dari client tidak diproses ulang.
+// StatusCode 0 berarti request pertama masih diproses.
+type IdempotencyKey struct {
+	Username     string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// percobaan login dihitung per username dan per ip
+const (
+	LoginAttemptUsername = "username"
+	LoginAttemptIp       = "ip"
+)
+
+type LoginAttempt struct {
+	Kind         string
+	Value        string
+	FailedCount  int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+type LoginLockout struct {
+	Id          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Value       string     `json:"value"`
+	FailedCount int        `json:"failedCount"`
+	LockedAt    time.Time  `json:"lockedAt"`
+	LockedUntil time.Time  `json:"lockedUntil"`
+	UnlockedBy  string     `json:"unlockedBy,omitempty"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+}
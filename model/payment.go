@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	PaymentMethodCash     = "cash"
+	PaymentMethodTransfer = "transfer"
+	PaymentMethodQris     = "qris"
+)
+
+// status pembayaran sebuah bill, dihitung dari total pembayaran yang masuk
+const (
+	PaymentStatusUnpaid        = "unpaid"
+	PaymentStatusPartiallyPaid = "partially_paid"
+	PaymentStatusPaid          = "paid"
+)
+
+type Payment struct {
+	Id          string    `json:"id"`
+	BillId      string    `json:"billId"`
+	Amount      int       `json:"amount"`
+	Method      string    `json:"method"`
+	PaymentDate time.Time `json:"paymentDate"`
+	ReceivedBy  string    `json:"receivedBy"`
+	// diisi ID pembayaran asal kalau baris ini adalah pembalik dari void bill
+	ReversalOf string `json:"reversalOf,omitempty"`
+}
@@ -1,8 +1,13 @@
 package model
 
+import "time"
+
 type Product struct {
 	Id string
 	Name string
 	Price int
+	TurnaroundHours int
 	Uom Uom
+	Version int
+	DeletedAt *time.Time `json:",omitempty"`
 }
\ No newline at end of file
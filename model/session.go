@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// satu session dibuat setiap login, semua refresh token hasil rotasi menempel ke session yang sama
+type Session struct {
+	Id        string
+	UserId    string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+type RefreshToken struct {
+	Id        string
+	SessionId string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -1,6 +1,10 @@
 package model
 
+import "time"
+
 type Uom struct {
 	Id string `json:"id"`
 	Name string `json:"name"`
+	Version int `json:"version"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
\ No newline at end of file
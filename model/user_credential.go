@@ -1,8 +1,18 @@
 package model
 
+const (
+	RoleOwner    = "owner"
+	RoleAdmin    = "admin"
+	RoleCashier  = "cashier"
+	RoleOperator = "operator"
+)
+
 type UserCredential struct {
 	Id       string `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"password,omitempty"`
+	Role     string `json:"role"`
 	IsActive bool   `json:"isActive"`
+	// karyawan yang tercatat di bill saat user ini membuat bill
+	EmployeeId string `json:"employeeId,omitempty"`
 }
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+)
+
+type AuditRepository interface {
+	Create(payload model.AuditLog) error
+	Paging(filter dto.AuditFilterDto) ([]model.AuditLog, dto.Paging, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// Create implements AuditRepository.
+func (a *auditRepository) Create(payload model.AuditLog) error {
+	_, err := a.db.Exec("INSERT INTO audit_log (id, actor, action, entity_type, entity_id, before_data, after_data, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		payload.Id, payload.Actor, payload.Action, payload.EntityType, payload.EntityId, nullJSON(payload.Before), nullJSON(payload.After), payload.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Paging implements AuditRepository.
+func (a *auditRepository) Paging(filter dto.AuditFilterDto) ([]model.AuditLog, dto.Paging, error) {
+	paginationQuery := common.GetPaginationParams(dto.PaginationParam{Page: filter.Page, Limit: filter.Limit})
+
+	where, args := auditPagingFilter(filter)
+	query := fmt.Sprintf("SELECT id, actor, action, entity_type, entity_id, before_data, after_data, created_at FROM audit_log %s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d", where, len(args)+1, len(args)+2)
+	rows, err := a.db.Query(query, append(args, paginationQuery.Take, paginationQuery.Skip)...)
+	if err != nil {
+		return nil, dto.Paging{}, err
+	}
+	defer rows.Close()
+
+	var audits []model.AuditLog
+	for rows.Next() {
+		var audit model.AuditLog
+		var before, after []byte
+		err := rows.Scan(&audit.Id, &audit.Actor, &audit.Action, &audit.EntityType, &audit.EntityId, &before, &after, &audit.CreatedAt)
+		if err != nil {
+			return nil, dto.Paging{}, err
+		}
+		audit.Before = before
+		audit.After = after
+		audits = append(audits, audit)
+	}
+
+	var totalRows int
+	err = a.db.QueryRow("SELECT COUNT(*) FROM audit_log "+where, args...).Scan(&totalRows)
+	if err != nil {
+		return nil, dto.Paging{}, err
+	}
+
+	return audits, common.Paginate(paginationQuery.Page, paginationQuery.Take, totalRows), nil
+}
+
+func auditPagingFilter(filter dto.AuditFilterDto) (string, []any) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityId != "" {
+		addCondition("entity_id = $%d", filter.EntityId)
+	}
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if !filter.DateFrom.IsZero() {
+		addCondition("created_at >= $%d", filter.DateFrom.Format("2006-01-02"))
+	}
+	if !filter.DateTo.IsZero() {
+		addCondition("created_at < $%d", filter.DateTo.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// data kosong disimpan sebagai NULL, misalnya before pada create dan after pada delete
+func nullJSON(data json.RawMessage) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
@@ -1,15 +1,73 @@
 package repository
 
-import "github.com/NursiNursi/laundry-apps/model/dto"
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
 
 type BaseRepository[T any] interface {
 	Create(payload T) error
 	List() ([]T, error)
 	Get(id string) (T, error)
+	// GetIncludeDeleted sama dengan Get tapi ikut mencari data yang sudah di soft delete
+	GetIncludeDeleted(id string) (T, error)
+	// Update hanya berhasil kalau Version di payload sama dengan version di database
 	Update(payload T) error
+	// Delete hanya mengisi deleted_at supaya bill lama tetap bisa menunjuk ke data ini
 	Delete(id string) error
+	Restore(id string) error
 }
 
 type BaseRepositoryPaging[T any] interface {
 	Paging(requestPaging dto.PaginationParam) ([]T, dto.Paging, error)
 }
+
+// notDeleted membuat kondisi untuk menyembunyikan data yang sudah di soft delete
+func notDeleted(column string, includeDeleted bool) string {
+	if includeDeleted {
+		return ""
+	}
+	return "WHERE " + column + " IS NULL"
+}
+
+// checkVersion mengubah update yang tidak mengenai baris apapun menjadi ErrVersionConflict
+func checkVersion(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return exceptions.ErrVersionConflict
+	}
+	return nil
+}
+
+// format teks waktu yang dikembalikan sqlite untuk hasil fungsi, seperti MIN atau MAX dari kolom timestamp
+var textTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// nullTime sama dengan sql.NullTime tapi juga menerima teks, karena sqlite hanya mengubah
+// nilai kolom timestamp menjadi time.Time kalau diambil langsung dari kolomnya
+type nullTime struct {
+	sql.NullTime
+}
+
+func (n *nullTime) Scan(value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return n.NullTime.Scan(value)
+	}
+	for _, format := range textTimeFormats {
+		if t, err := time.Parse(format, text); err == nil {
+			n.Time, n.Valid = t, true
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported time format %q", text)
+}
@@ -2,16 +2,24 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type BillRepository interface {
 	Create(payload model.Bill) error
 	Get(id string) (dto.BillResponseDto, error)
 	BaseRepositoryPaging[dto.BillResponseDto]
+	UpdateStatus(payload model.BillStatusHistory) error
+	ListStatusHistory(billId string) ([]model.BillStatusHistory, error)
+	Void(payload model.BillVoid) error
+	UpdateDetails(payload model.Bill) error
+	GetCustomerSummary(customerId string) (dto.CustomerSummaryDto, error)
 	// Paging(requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error)
 }
 
@@ -26,7 +34,8 @@ func (b *billRepository) Create(payload model.Bill) error {
 		return err
 	}
 	// insert bill
-	_, err = tx.Exec("INSERT INTO bill (id, bill_date, entry_date, finish_date, employee_id, customer_id) VALUES ($1, $2, $3, $4, $5, $6)", payload.Id, payload.BillDate, payload.EntryDate, payload.FinishDate, payload.EmployeeId, payload.CustomerId)
+	// bill_date dikirim sebagai teks YYYY-MM-DD supaya sqlite juga hanya menyimpan tanggalnya seperti kolom date di postgres
+	_, err = tx.Exec("INSERT INTO bill (id, bill_date, entry_date, finish_date, employee_id, customer_id, status) VALUES ($1, $2, $3, $4, $5, $6, $7)", payload.Id, payload.BillDate.Format("2006-01-02"), payload.EntryDate, payload.FinishDate, payload.EmployeeId, payload.CustomerId, payload.Status)
 
 	if err != nil {
 		return err
@@ -47,16 +56,22 @@ func (b *billRepository) Create(payload model.Bill) error {
 // Get implements BillRepository.
 func (b *billRepository) Get(id string) (dto.BillResponseDto, error) {
 	var billResponseDto dto.BillResponseDto
-	sqlBill := `SELECT b.id as bill_id, b.bill_date, b.entry_date, b.finish_date, c.id as customer_id, c.name as customer_name, c.phone_number as customer_phone, c.address as customer_address, e.id as employee_id, e.name as employee_name, e.phone_number as employee_phone, e.address as employee_address
+	sqlBill := `SELECT b.id as bill_id, b.bill_date, b.entry_date, b.finish_date, b.status, c.id as customer_id, c.name as customer_name, c.phone_number as customer_phone, c.address as customer_address, e.id as employee_id, e.name as employee_name, e.phone_number as employee_phone, e.address as employee_address,
+	(SELECT COALESCE(SUM(py.amount), 0) FROM payment py WHERE py.bill_id = b.id) as amount_paid,
+	COALESCE(b.void_reason, ''), COALESCE(b.voided_by, ''), b.voided_at
 	FROM bill b 
 	JOIN customer c ON c.id = b.customer_id 
 	JOIN employee e ON e.id = b.employee_id
 	WHERE b.id = $1`
 
-	err := b.db.QueryRow(sqlBill, id).Scan(&billResponseDto.Id, &billResponseDto.BillDate, &billResponseDto.EntryDate, &billResponseDto.FinishDate, &billResponseDto.Customer.Id, &billResponseDto.Customer.Name, &billResponseDto.Customer.PhoneNumber, &billResponseDto.Customer.Address, &billResponseDto.Employee.Id, &billResponseDto.Employee.Name, &billResponseDto.Employee.PhoneNumber, &billResponseDto.Employee.Address)
+	var voidedAt sql.NullTime
+	err := b.db.QueryRow(sqlBill, id).Scan(&billResponseDto.Id, &billResponseDto.BillDate, &billResponseDto.EntryDate, &billResponseDto.FinishDate, &billResponseDto.Status, &billResponseDto.Customer.Id, &billResponseDto.Customer.Name, &billResponseDto.Customer.PhoneNumber, &billResponseDto.Customer.Address, &billResponseDto.Employee.Id, &billResponseDto.Employee.Name, &billResponseDto.Employee.PhoneNumber, &billResponseDto.Employee.Address, &billResponseDto.AmountPaid, &billResponseDto.VoidReason, &billResponseDto.VoidedBy, &voidedAt)
 	if err != nil {
 		return dto.BillResponseDto{}, err
 	}
+	if voidedAt.Valid {
+		billResponseDto.VoidedAt = &voidedAt.Time
+	}
 
 	sqlBillDetail := `SELECT b.id as bill_id, p.id as product_id, p.name as product_name, p.price, u.id as uom_id, u.name as uom_name, bd.id as bill_detail_id, bd.product_price, bd.qty
 	FROM bill b 
@@ -88,23 +103,38 @@ func (b *billRepository) Paging(requestPaging dto.PaginationParam) ([]dto.BillRe
 	var paginationQuery dto.PaginationQuery
 	paginationQuery = common.GetPaginationParams(requestPaging)
 
-	rows, err := b.db.Query(`SELECT b.id as bill_id, b.bill_date, b.entry_date, b.finish_date, c.id as customer_id, c.name as customer_name, c.phone_number as customer_phone, c.address as customer_address, e.id as employee_id, e.name as employee_name, e.phone_number as employee_phone, e.address as employee_address
-	FROM bill b JOIN customer c ON c.id = b.customer_id	JOIN employee e ON e.id = b.employee_id LIMIT $1 OFFSET $2`, paginationQuery.Take, paginationQuery.Skip)
+	where, args := billPagingFilter(requestPaging)
+	orderBy, err := billPagingOrder(requestPaging)
+	if err != nil {
+		return nil, dto.Paging{}, err
+	}
+	sqlBill := fmt.Sprintf(`SELECT b.id as bill_id, b.bill_date, b.entry_date, b.finish_date, b.status, c.id as customer_id, c.name as customer_name, c.phone_number as customer_phone, c.address as customer_address, e.id as employee_id, e.name as employee_name, e.phone_number as employee_phone, e.address as employee_address,
+	(SELECT COALESCE(SUM(bd.product_price * bd.qty), 0) FROM bill_detail bd WHERE bd.bill_id = b.id) as total_bill,
+	(SELECT COALESCE(SUM(py.amount), 0) FROM payment py WHERE py.bill_id = b.id) as amount_paid,
+	COALESCE(b.void_reason, ''), COALESCE(b.voided_by, ''), b.voided_at
+	FROM bill b JOIN customer c ON c.id = b.customer_id	JOIN employee e ON e.id = b.employee_id %s %s LIMIT $%d OFFSET $%d`, where, orderBy, len(args)+1, len(args)+2)
+
+	rows, err := b.db.Query(sqlBill, append(args, paginationQuery.Take, paginationQuery.Skip)...)
 	if err != nil {
 		return nil, dto.Paging{}, err
 	}
+	defer rows.Close()
 	var bills []dto.BillResponseDto
 	for rows.Next() {
 		var bill dto.BillResponseDto
-		err := rows.Scan(&bill.Id, &bill.BillDate, &bill.EntryDate, &bill.FinishDate, &bill.Customer.Id, &bill.Customer.Name, &bill.Customer.PhoneNumber, &bill.Customer.Address, &bill.Employee.Id, &bill.Employee.Name, &bill.Employee.PhoneNumber, &bill.Employee.Address)
+		var voidedAt sql.NullTime
+		err := rows.Scan(&bill.Id, &bill.BillDate, &bill.EntryDate, &bill.FinishDate, &bill.Status, &bill.Customer.Id, &bill.Customer.Name, &bill.Customer.PhoneNumber, &bill.Customer.Address, &bill.Employee.Id, &bill.Employee.Name, &bill.Employee.PhoneNumber, &bill.Employee.Address, &bill.TotalBill, &bill.AmountPaid, &bill.VoidReason, &bill.VoidedBy, &voidedAt)
 		if err != nil {
 			return nil, dto.Paging{}, err
 		}
+		if voidedAt.Valid {
+			bill.VoidedAt = &voidedAt.Time
+		}
 		bills = append(bills, bill)
 	}
 
 	var totalRows int
-	row := b.db.QueryRow("SELECT COUNT(*) FROM bill")
+	row := b.db.QueryRow("SELECT COUNT(*) FROM bill b "+where, args...)
 	err = row.Scan(&totalRows)
 	if err != nil {
 		return nil, dto.Paging{}, err
@@ -113,6 +143,285 @@ func (b *billRepository) Paging(requestPaging dto.PaginationParam) ([]dto.BillRe
 	return bills, common.Paginate(paginationQuery.Page, paginationQuery.Take, totalRows), nil
 }
 
+// UpdateStatus implements BillRepository.
+func (b *billRepository) UpdateStatus(payload model.BillStatusHistory) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// hanya update kalau status di database masih sama, supaya transisi tidak saling menimpa
+	result, err := tx.Exec("UPDATE bill SET status = $2 WHERE id = $1 AND status = $3", payload.BillId, payload.ToStatus, payload.FromStatus)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.FromStatus)
+	}
+
+	_, err = tx.Exec("INSERT INTO bill_status_history (id, bill_id, from_status, to_status, changed_by, changed_at) VALUES ($1, $2, $3, $4, $5, $6)", payload.Id, payload.BillId, payload.FromStatus, payload.ToStatus, payload.ChangedBy, payload.ChangedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListStatusHistory implements BillRepository.
+func (b *billRepository) ListStatusHistory(billId string) ([]model.BillStatusHistory, error) {
+	rows, err := b.db.Query("SELECT id, bill_id, from_status, to_status, changed_by, changed_at FROM bill_status_history WHERE bill_id = $1 ORDER BY changed_at", billId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []model.BillStatusHistory
+	for rows.Next() {
+		var history model.BillStatusHistory
+		err := rows.Scan(&history.Id, &history.BillId, &history.FromStatus, &history.ToStatus, &history.ChangedBy, &history.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// Void implements BillRepository.
+func (b *billRepository) Void(payload model.BillVoid) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE bill SET status = $2, void_reason = $3, voided_by = $4, voided_at = $5 WHERE id = $1 AND status = $6", payload.BillId, model.BillStatusCancelled, payload.Reason, payload.VoidedBy, payload.VoidedAt, payload.FromStatus)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.FromStatus)
+	}
+
+	_, err = tx.Exec("INSERT INTO bill_status_history (id, bill_id, from_status, to_status, changed_by, changed_at) VALUES ($1, $2, $3, $4, $5, $6)", common.GenerateID(), payload.BillId, payload.FromStatus, model.BillStatusCancelled, payload.VoidedBy, payload.VoidedAt)
+	if err != nil {
+		return err
+	}
+
+	// setiap pembayaran dibuatkan baris pembalik dengan nominal negatif, data aslinya tidak dihapus
+	rows, err := tx.Query("SELECT id, amount, method FROM payment WHERE bill_id = $1 AND reversal_of IS NULL", payload.BillId)
+	if err != nil {
+		return err
+	}
+	var reversals []model.Payment
+	for rows.Next() {
+		var payment model.Payment
+		if err := rows.Scan(&payment.Id, &payment.Amount, &payment.Method); err != nil {
+			rows.Close()
+			return err
+		}
+		reversals = append(reversals, model.Payment{
+			Id:          common.GenerateID(),
+			BillId:      payload.BillId,
+			Amount:      -payment.Amount,
+			Method:      payment.Method,
+			PaymentDate: payload.VoidedAt,
+			ReceivedBy:  payload.VoidedBy,
+			ReversalOf:  payment.Id,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, reversal := range reversals {
+		_, err = tx.Exec("INSERT INTO payment (id, bill_id, amount, method, payment_date, received_by, reversal_of) VALUES ($1, $2, $3, $4, $5, $6, $7)", reversal.Id, reversal.BillId, reversal.Amount, reversal.Method, reversal.PaymentDate, reversal.ReceivedBy, reversal.ReversalOf)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateDetails implements BillRepository.
+func (b *billRepository) UpdateDetails(payload model.Bill) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// status di cek lagi supaya bill yang barusan selesai / di void tidak ikut berubah,
+	// update ini juga mengunci baris bill sama seperti paymentRepository.Create
+	result, err := tx.Exec("UPDATE bill SET finish_date = $2 WHERE id = $1 AND status = $3", payload.Id, payload.FinishDate, payload.Status)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.Status)
+	}
+
+	// pembayaran dihitung ulang setelah baris bill terkunci supaya pembayaran yang masuk sejak bill dibaca ikut dihitung
+	var amountPaid int
+	err = tx.QueryRow("SELECT COALESCE(SUM(amount), 0) FROM payment WHERE bill_id = $1", payload.Id).Scan(&amountPaid)
+	if err != nil {
+		return err
+	}
+	if total := billDetailsTotal(payload.BillDetails); total < amountPaid {
+		return fmt.Errorf("%w: total %d, amount paid %d", exceptions.ErrBillTotalBelowAmountPaid, total, amountPaid)
+	}
+
+	// detail lama diganti seluruhnya dengan detail baru, ID detail yang masih ada tetap dipakai
+	_, err = tx.Exec("DELETE FROM bill_detail WHERE bill_id = $1", payload.Id)
+	if err != nil {
+		return err
+	}
+	for _, item := range payload.BillDetails {
+		_, err = tx.Exec("INSERT INTO bill_detail (id, bill_id, product_id, product_price, qty) VALUES ($1, $2, $3, $4, $5)", item.Id, item.BillId, item.ProductId, item.ProductPrice, item.Qty)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCustomerSummary implements BillRepository.
+func (b *billRepository) GetCustomerSummary(customerId string) (dto.CustomerSummaryDto, error) {
+	var summary dto.CustomerSummaryDto
+	var lastVisit nullTime
+	// bill yang di void tidak dihitung sebagai transaksi customer
+	sqlSummary := `SELECT COUNT(b.id), COALESCE(SUM(t.total), 0), MAX(b.entry_date)
+	FROM bill b
+	LEFT JOIN (SELECT bill_id, SUM(product_price * qty) as total FROM bill_detail GROUP BY bill_id) t ON t.bill_id = b.id
+	WHERE b.customer_id = $1 AND b.status <> $2`
+	err := b.db.QueryRow(sqlSummary, customerId, model.BillStatusCancelled).Scan(&summary.TotalOrders, &summary.TotalSpent, &lastVisit)
+	if err != nil {
+		return dto.CustomerSummaryDto{}, err
+	}
+	if lastVisit.Valid {
+		summary.LastVisit = &lastVisit.Time
+	}
+
+	sqlFavourite := `SELECT p.id, p.name, p.price, p.turnaround_hours, u.id, u.name, SUM(bd.qty) as total_qty, COUNT(DISTINCT b.id) as order_count
+	FROM bill b
+	JOIN bill_detail bd ON bd.bill_id = b.id
+	JOIN product p ON p.id = bd.product_id
+	JOIN uom u ON u.id = p.uom_id
+	WHERE b.customer_id = $1 AND b.status <> $2
+	GROUP BY p.id, p.name, p.price, p.turnaround_hours, u.id, u.name
+	ORDER BY order_count DESC, total_qty DESC
+	LIMIT 5`
+	rows, err := b.db.Query(sqlFavourite, customerId, model.BillStatusCancelled)
+	if err != nil {
+		return dto.CustomerSummaryDto{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var favourite dto.FavouriteProductDto
+		err := rows.Scan(&favourite.Product.Id, &favourite.Product.Name, &favourite.Product.Price, &favourite.Product.TurnaroundHours, &favourite.Product.Uom.Id, &favourite.Product.Uom.Name, &favourite.TotalQty, &favourite.OrderCount)
+		if err != nil {
+			return dto.CustomerSummaryDto{}, err
+		}
+		summary.FavouriteProducts = append(summary.FavouriteProducts, favourite)
+	}
+	return summary, nil
+}
+
+// kolom yang boleh dipakai untuk sort listing bill, selain ini ditolak
+var billSortColumns = map[string]string{
+	"billDate":     "b.bill_date",
+	"entryDate":    "b.entry_date",
+	"finishDate":   "b.finish_date",
+	"status":       "b.status",
+	"customerName": "c.name",
+	"employeeName": "e.name",
+	"totalBill":    "total_bill",
+}
+
+// billPagingFilter menyusun klausa WHERE untuk Paging dan COUNT supaya filternya selalu sama.
+// Tanggal "to" bersifat inklusif sampai akhir hari tersebut. Tanggal dikirim sebagai teks YYYY-MM-DD
+// supaya dibandingkan apa adanya tanpa konversi zona waktu.
+func billPagingFilter(requestPaging dto.PaginationParam) (string, []any) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if requestPaging.Status != "" {
+		addCondition("b.status = $%d", requestPaging.Status)
+	}
+	if requestPaging.CustomerId != "" {
+		addCondition("b.customer_id = $%d", requestPaging.CustomerId)
+	}
+	if requestPaging.EmployeeId != "" {
+		addCondition("b.employee_id = $%d", requestPaging.EmployeeId)
+	}
+	if !requestPaging.BillDateFrom.IsZero() {
+		addCondition("b.bill_date >= $%d", requestPaging.BillDateFrom.Format("2006-01-02"))
+	}
+	if !requestPaging.BillDateTo.IsZero() {
+		addCondition("b.bill_date < $%d", requestPaging.BillDateTo.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+	if !requestPaging.FinishDateFrom.IsZero() {
+		addCondition("b.finish_date >= $%d", requestPaging.FinishDateFrom.Format("2006-01-02"))
+	}
+	if !requestPaging.FinishDateTo.IsZero() {
+		addCondition("b.finish_date < $%d", requestPaging.FinishDateTo.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func billDetailsTotal(details []model.BillDetail) int {
+	var total int
+	for _, detail := range details {
+		total += detail.ProductPrice * detail.Qty
+	}
+	return total
+}
+
+// billPagingOrder menerjemahkan SortBy dan SortOrder ke klausa ORDER BY dari whitelist
+func billPagingOrder(requestPaging dto.PaginationParam) (string, error) {
+	column := "b.bill_date"
+	if requestPaging.SortBy != "" {
+		var ok bool
+		column, ok = billSortColumns[requestPaging.SortBy]
+		if !ok {
+			return "", fmt.Errorf("%w field %s", exceptions.ErrInvalidSort, requestPaging.SortBy)
+		}
+	}
+
+	direction := "DESC"
+	switch strings.ToLower(requestPaging.SortOrder) {
+	case "":
+	case "asc":
+		direction = "ASC"
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", fmt.Errorf("%w order %s", exceptions.ErrInvalidSort, requestPaging.SortOrder)
+	}
+	// b.id sebagai pengurut kedua supaya hasil paging stabil
+	return fmt.Sprintf("ORDER BY %s %s, b.id", column, direction), nil
+}
+
 func NewBillRepository(db *sql.DB) BillRepository {
 	return &billRepository{db: db}
 }
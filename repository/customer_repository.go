@@ -11,7 +11,8 @@ import (
 type CustomerRepository interface {
 	BaseRepository[model.Customer]
 	BaseRepositoryPaging[model.Customer]
-	GetPhoneNumber(phoneNumber string) (model.Customer, error)
+	// GetPhoneNumber hanya mencari data yang belum di soft delete kecuali includeDeleted true
+	GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Customer, error)
 }
 
 type customerRepository struct {
@@ -29,7 +30,7 @@ func (c *customerRepository) Create(payload model.Customer) error {
 
 // Delete implements CustomerRepository.
 func (c *customerRepository) Delete(id string) error {
-	_, err := c.db.Exec("DELETE FROM customer WHERE id=$1", id)
+	_, err := c.db.Exec("UPDATE customer SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
 	}
@@ -38,35 +39,64 @@ func (c *customerRepository) Delete(id string) error {
 
 // Get implements CustomerRepository.
 func (c *customerRepository) Get(id string) (model.Customer, error) {
+	return c.get("SELECT id, name, phone_number, address, version, deleted_at FROM customer WHERE id=$1 AND deleted_at IS NULL", id)
+}
+
+// GetIncludeDeleted implements CustomerRepository.
+func (c *customerRepository) GetIncludeDeleted(id string) (model.Customer, error) {
+	return c.get("SELECT id, name, phone_number, address, version, deleted_at FROM customer WHERE id=$1", id)
+}
+
+func (c *customerRepository) get(query string, id string) (model.Customer, error) {
 	var customer model.Customer
-	err := c.db.QueryRow("SELECT id, name, phone_number, address FROM customer WHERE id=$1", id).Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address)
+	var deletedAt sql.NullTime
+	err := c.db.QueryRow(query, id).Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address, &customer.Version, &deletedAt)
 	if err != nil {
 		return model.Customer{}, err
 	}
+	if deletedAt.Valid {
+		customer.DeletedAt = &deletedAt.Time
+	}
 	return customer, nil
+}
 
+// Restore implements CustomerRepository.
+func (c *customerRepository) Restore(id string) error {
+	_, err := c.db.Exec("UPDATE customer SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
 // GetEmail implements CustomerRepository.
-func (c *customerRepository) GetPhoneNumber(phoneNumber string) (model.Customer, error) {
+func (c *customerRepository) GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Customer, error) {
+	query := "SELECT id, name, phone_number, address, deleted_at FROM customer WHERE phone_number=$1"
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 	var customer model.Customer
-	err := c.db.QueryRow("SELECT id, name, phone_number, address FROM customer WHERE phone_number=$1", phoneNumber).Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address)
+	var deletedAt sql.NullTime
+	err := c.db.QueryRow(query, phoneNumber).Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address, &deletedAt)
 	if err != nil {
 		return model.Customer{}, err
 	}
+	if deletedAt.Valid {
+		customer.DeletedAt = &deletedAt.Time
+	}
 	return customer, nil
 }
 
 // List implements CustomerRepository.
 func (c *customerRepository) List() ([]model.Customer, error) {
-	rows, err := c.db.Query("SELECT id, name, phone_number, address FROM customer")
+	rows, err := c.db.Query("SELECT id, name, phone_number, address, version FROM customer WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
 	var customers []model.Customer
 	for rows.Next() {
 		var customer model.Customer
-		err := rows.Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address)
+		err := rows.Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address, &customer.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -79,23 +109,28 @@ func (c *customerRepository) List() ([]model.Customer, error) {
 func (c *customerRepository) Paging(requestPaging dto.PaginationParam) ([]model.Customer, dto.Paging, error) {
 	var paginationQuery dto.PaginationQuery
 	paginationQuery = common.GetPaginationParams(requestPaging)
-	rows, err := c.db.Query("SELECT id, name, phone_number, address FROM customer LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
+	where := notDeleted("deleted_at", requestPaging.IncludeDeleted)
+	rows, err := c.db.Query("SELECT id, name, phone_number, address, version, deleted_at FROM customer "+where+" LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
 	if err != nil {
 		return nil, dto.Paging{}, err
 	}
 	var customers []model.Customer
 	for rows.Next() {
 		var customer model.Customer
-		err := rows.Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address)
+		var deletedAt sql.NullTime
+		err := rows.Scan(&customer.Id, &customer.Name, &customer.PhoneNumber, &customer.Address, &customer.Version, &deletedAt)
 		if err != nil {
 			return nil, dto.Paging{}, err
 		}
+		if deletedAt.Valid {
+			customer.DeletedAt = &deletedAt.Time
+		}
 		customers = append(customers, customer)
 	}
 
 	// count product
 	var totalRows int
-	row := c.db.QueryRow("SELECT COUNT(*) FROM customer")
+	row := c.db.QueryRow("SELECT COUNT(*) FROM customer " + where)
 	err = row.Scan(&totalRows)
 	if err != nil {
 		return nil, dto.Paging{}, err
@@ -106,11 +141,12 @@ func (c *customerRepository) Paging(requestPaging dto.PaginationParam) ([]model.
 
 // Update implements CustomerRepository.
 func (c *customerRepository) Update(payload model.Customer) error {
-	_, err := c.db.Exec("UPDATE customer SET name = $2, phone_number = $3, address = $4 WHERE id = $1", payload.Id, payload.Name, payload.PhoneNumber, payload.Address)
+	// hanya update kalau version masih sama dengan yang dibaca client
+	result, err := c.db.Exec("UPDATE customer SET name = $2, phone_number = $3, address = $4, version = version + 1 WHERE id = $1 AND version = $5 AND deleted_at IS NULL", payload.Id, payload.Name, payload.PhoneNumber, payload.Address, payload.Version)
 	if err != nil {
 		return err
 	}
-	return nil
+	return checkVersion(result)
 }
 
 func NewCustomerRepository(db *sql.DB) CustomerRepository {
@@ -11,7 +11,8 @@ import (
 type EmployeeRepository interface {
 	BaseRepository[model.Employee]
 	BaseRepositoryPaging[model.Employee]
-	GetPhoneNumber(phoneNumber string) (model.Employee, error)
+	// GetPhoneNumber hanya mencari data yang belum di soft delete kecuali includeDeleted true
+	GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Employee, error)
 }
 
 type employeeRepository struct {
@@ -29,7 +30,7 @@ func (e *employeeRepository) Create(payload model.Employee) error {
 
 // Delete implements employeeRepository.
 func (e *employeeRepository) Delete(id string) error {
-	_, err := e.db.Exec("DELETE FROM employee WHERE id=$1", id)
+	_, err := e.db.Exec("UPDATE employee SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
 	}
@@ -38,35 +39,64 @@ func (e *employeeRepository) Delete(id string) error {
 
 // Get implements employeeRepository.
 func (e *employeeRepository) Get(id string) (model.Employee, error) {
+	return e.get("SELECT id, name, phone_number, address, version, deleted_at FROM employee WHERE id=$1 AND deleted_at IS NULL", id)
+}
+
+// GetIncludeDeleted implements employeeRepository.
+func (e *employeeRepository) GetIncludeDeleted(id string) (model.Employee, error) {
+	return e.get("SELECT id, name, phone_number, address, version, deleted_at FROM employee WHERE id=$1", id)
+}
+
+func (e *employeeRepository) get(query string, id string) (model.Employee, error) {
 	var employee model.Employee
-	err := e.db.QueryRow("SELECT id, name, phone_number, address FROM employee WHERE id=$1", id).Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address)
+	var deletedAt sql.NullTime
+	err := e.db.QueryRow(query, id).Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address, &employee.Version, &deletedAt)
 	if err != nil {
 		return model.Employee{}, err
 	}
+	if deletedAt.Valid {
+		employee.DeletedAt = &deletedAt.Time
+	}
 	return employee, nil
+}
 
+// Restore implements employeeRepository.
+func (e *employeeRepository) Restore(id string) error {
+	_, err := e.db.Exec("UPDATE employee SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
 // GetEmail implements employeeRepository.
-func (e *employeeRepository) GetPhoneNumber(phoneNumber string) (model.Employee, error) {
+func (e *employeeRepository) GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Employee, error) {
+	query := "SELECT id, name, phone_number, address, deleted_at FROM employee WHERE phone_number=$1"
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 	var employee model.Employee
-	err := e.db.QueryRow("SELECT id, name, phone_number, address FROM employee WHERE phone_number=$1", phoneNumber).Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address)
+	var deletedAt sql.NullTime
+	err := e.db.QueryRow(query, phoneNumber).Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address, &deletedAt)
 	if err != nil {
 		return model.Employee{}, err
 	}
+	if deletedAt.Valid {
+		employee.DeletedAt = &deletedAt.Time
+	}
 	return employee, nil
 }
 
 // List implements employeeRepository.
 func (e *employeeRepository) List() ([]model.Employee, error) {
-	rows, err := e.db.Query("SELECT id, name, phone_number, address FROM employee")
+	rows, err := e.db.Query("SELECT id, name, phone_number, address, version FROM employee WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
 	var employees []model.Employee
 	for rows.Next() {
 		var employee model.Employee
-		err := rows.Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address)
+		err := rows.Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address, &employee.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -79,23 +109,28 @@ func (e *employeeRepository) List() ([]model.Employee, error) {
 func (e *employeeRepository) Paging(requestPaging dto.PaginationParam) ([]model.Employee, dto.Paging, error) {
 	var paginationQuery dto.PaginationQuery
 	paginationQuery = common.GetPaginationParams(requestPaging)
-	rows, err := e.db.Query("SELECT id, name, phone_number, address FROM employee LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
+	where := notDeleted("deleted_at", requestPaging.IncludeDeleted)
+	rows, err := e.db.Query("SELECT id, name, phone_number, address, version, deleted_at FROM employee "+where+" LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
 	if err != nil {
 		return nil, dto.Paging{}, err
 	}
 	var employees []model.Employee
 	for rows.Next() {
 		var employee model.Employee
-		err := rows.Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address)
+		var deletedAt sql.NullTime
+		err := rows.Scan(&employee.Id, &employee.Name, &employee.PhoneNumber, &employee.Address, &employee.Version, &deletedAt)
 		if err != nil {
 			return nil, dto.Paging{}, err
 		}
+		if deletedAt.Valid {
+			employee.DeletedAt = &deletedAt.Time
+		}
 		employees = append(employees, employee)
 	}
 
 	// count product
 	var totalRows int
-	row := e.db.QueryRow("SELECT COUNT(*) FROM employee")
+	row := e.db.QueryRow("SELECT COUNT(*) FROM employee " + where)
 	err = row.Scan(&totalRows)
 	if err != nil {
 		return nil, dto.Paging{}, err
@@ -106,11 +141,12 @@ func (e *employeeRepository) Paging(requestPaging dto.PaginationParam) ([]model.
 
 // Update implements employeeRepository.
 func (e *employeeRepository) Update(payload model.Employee) error {
-	_, err := e.db.Exec("UPDATE employee SET name = $2, phone_number = $3, address = $4 WHERE id = $1", payload.Id, payload.Name, payload.PhoneNumber, payload.Address)
+	// hanya update kalau version masih sama dengan yang dibaca client
+	result, err := e.db.Exec("UPDATE employee SET name = $2, phone_number = $3, address = $4, version = version + 1 WHERE id = $1 AND version = $5 AND deleted_at IS NULL", payload.Id, payload.Name, payload.PhoneNumber, payload.Address, payload.Version)
 	if err != nil {
 		return err
 	}
-	return nil
+	return checkVersion(result)
 }
 
 func NewEmployeeRepository(db *sql.DB) EmployeeRepository {
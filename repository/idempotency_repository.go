@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+type IdempotencyRepository interface {
+	Create(payload model.IdempotencyKey) (bool, error)
+	Get(username string, key string) (model.IdempotencyKey, error)
+	Complete(payload model.IdempotencyKey) error
+	Delete(username string, key string) error
+	DeleteExpired(before time.Time) error
+}
+
+type idempotencyRepository struct {
+	db *sql.DB
+}
+
+// Create implements IdempotencyRepository.
+// mengembalikan false kalau key sudah dipakai, sehingga hanya satu request yang lanjut diproses
+func (i *idempotencyRepository) Create(payload model.IdempotencyKey) (bool, error) {
+	result, err := i.db.Exec(`INSERT INTO idempotency_key (username, idempotency_key, request_hash, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (username, idempotency_key) DO NOTHING`, payload.Username, payload.Key, payload.RequestHash, payload.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// Get implements IdempotencyRepository.
+func (i *idempotencyRepository) Get(username string, key string) (model.IdempotencyKey, error) {
+	var idempotencyKey model.IdempotencyKey
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err := i.db.QueryRow("SELECT username, idempotency_key, request_hash, status_code, content_type, response_body, created_at FROM idempotency_key WHERE username = $1 AND idempotency_key = $2", username, key).Scan(
+		&idempotencyKey.Username, &idempotencyKey.Key, &idempotencyKey.RequestHash, &statusCode, &contentType, &idempotencyKey.ResponseBody, &idempotencyKey.CreatedAt)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	idempotencyKey.StatusCode = int(statusCode.Int64)
+	idempotencyKey.ContentType = contentType.String
+	return idempotencyKey, nil
+}
+
+// Complete implements IdempotencyRepository.
+func (i *idempotencyRepository) Complete(payload model.IdempotencyKey) error {
+	_, err := i.db.Exec("UPDATE idempotency_key SET status_code = $3, content_type = $4, response_body = $5 WHERE username = $1 AND idempotency_key = $2",
+		payload.Username, payload.Key, payload.StatusCode, payload.ContentType, payload.ResponseBody)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete implements IdempotencyRepository.
+func (i *idempotencyRepository) Delete(username string, key string) error {
+	_, err := i.db.Exec("DELETE FROM idempotency_key WHERE username = $1 AND idempotency_key = $2", username, key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired implements IdempotencyRepository.
+func (i *idempotencyRepository) DeleteExpired(before time.Time) error {
+	_, err := i.db.Exec("DELETE FROM idempotency_key WHERE created_at < $1", before)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
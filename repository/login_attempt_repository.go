@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+type LoginAttemptRepository interface {
+	Get(kind string, value string) (model.LoginAttempt, error)
+	RecordFailure(kind string, value string, failedAt time.Time, windowStart time.Time) (int, error)
+	Lock(kind string, value string, lockedUntil time.Time) error
+	Reset(kind string, value string) error
+	CreateLockout(payload model.LoginLockout) error
+	GetLockout(id string) (model.LoginLockout, error)
+	ListLockouts(activeAt *time.Time) ([]model.LoginLockout, error)
+	Unlock(id string, unlockedBy string, unlockedAt time.Time) error
+}
+
+type loginAttemptRepository struct {
+	db *sql.DB
+}
+
+// Get implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Get(kind string, value string) (model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	var lockedUntil sql.NullTime
+	err := l.db.QueryRow("SELECT kind, value, failed_count, last_failed_at, locked_until FROM login_attempt WHERE kind = $1 AND value = $2", kind, value).Scan(&attempt.Kind, &attempt.Value, &attempt.FailedCount, &attempt.LastFailedAt, &lockedUntil)
+	if err != nil {
+		return model.LoginAttempt{}, err
+	}
+	if lockedUntil.Valid {
+		attempt.LockedUntil = &lockedUntil.Time
+	}
+	return attempt, nil
+}
+
+// RecordFailure implements LoginAttemptRepository.
+func (l *loginAttemptRepository) RecordFailure(kind string, value string, failedAt time.Time, windowStart time.Time) (int, error) {
+	// hitungan mulai dari awal lagi kalau gagal terakhir sudah di luar window
+	var failedCount int
+	err := l.db.QueryRow(`INSERT INTO login_attempt (kind, value, failed_count, last_failed_at) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (kind, value) DO UPDATE SET
+			failed_count = CASE WHEN login_attempt.last_failed_at < $4 THEN 1 ELSE login_attempt.failed_count + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failed_count`, kind, value, failedAt, windowStart).Scan(&failedCount)
+	if err != nil {
+		return 0, err
+	}
+	return failedCount, nil
+}
+
+// Lock implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Lock(kind string, value string, lockedUntil time.Time) error {
+	_, err := l.db.Exec("UPDATE login_attempt SET locked_until = $3 WHERE kind = $1 AND value = $2", kind, value, lockedUntil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reset implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Reset(kind string, value string) error {
+	_, err := l.db.Exec("DELETE FROM login_attempt WHERE kind = $1 AND value = $2", kind, value)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateLockout implements LoginAttemptRepository.
+func (l *loginAttemptRepository) CreateLockout(payload model.LoginLockout) error {
+	_, err := l.db.Exec("INSERT INTO login_lockout (id, kind, value, failed_count, locked_at, locked_until) VALUES ($1, $2, $3, $4, $5, $6)", payload.Id, payload.Kind, payload.Value, payload.FailedCount, payload.LockedAt, payload.LockedUntil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLockout implements LoginAttemptRepository.
+func (l *loginAttemptRepository) GetLockout(id string) (model.LoginLockout, error) {
+	row := l.db.QueryRow("SELECT id, kind, value, failed_count, locked_at, locked_until, unlocked_by, unlocked_at FROM login_lockout WHERE id = $1", id)
+	return scanLoginLockout(row)
+}
+
+// ListLockouts implements LoginAttemptRepository.
+func (l *loginAttemptRepository) ListLockouts(activeAt *time.Time) ([]model.LoginLockout, error) {
+	query := "SELECT id, kind, value, failed_count, locked_at, locked_until, unlocked_by, unlocked_at FROM login_lockout"
+	var args []any
+	// activeAt diisi untuk hanya menampilkan lockout yang masih berlaku
+	if activeAt != nil {
+		query += " WHERE unlocked_at IS NULL AND locked_until > $1"
+		args = append(args, *activeAt)
+	}
+	query += " ORDER BY locked_at DESC"
+
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lockouts []model.LoginLockout
+	for rows.Next() {
+		lockout, err := scanLoginLockout(rows)
+		if err != nil {
+			return nil, err
+		}
+		lockouts = append(lockouts, lockout)
+	}
+	return lockouts, rows.Err()
+}
+
+// Unlock implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Unlock(id string, unlockedBy string, unlockedAt time.Time) error {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var kind, value string
+	err = tx.QueryRow("UPDATE login_lockout SET unlocked_by = $2, unlocked_at = $3 WHERE id = $1 RETURNING kind, value", id, unlockedBy, unlockedAt).Scan(&kind, &value)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM login_attempt WHERE kind = $1 AND value = $2", kind, value)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLoginLockout(row rowScanner) (model.LoginLockout, error) {
+	var lockout model.LoginLockout
+	var unlockedBy sql.NullString
+	var unlockedAt sql.NullTime
+	err := row.Scan(&lockout.Id, &lockout.Kind, &lockout.Value, &lockout.FailedCount, &lockout.LockedAt, &lockout.LockedUntil, &unlockedBy, &unlockedAt)
+	if err != nil {
+		return model.LoginLockout{}, err
+	}
+	lockout.UnlockedBy = unlockedBy.String
+	if unlockedAt.Valid {
+		lockout.UnlockedAt = &unlockedAt.Time
+	}
+	return lockout, nil
+}
+
+func NewLoginAttemptRepository(db *sql.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
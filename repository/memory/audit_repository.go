@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+type auditRepository struct {
+	store *Store
+}
+
+// Create implements AuditRepository.
+func (a *auditRepository) Create(payload model.AuditLog) error {
+	a.store.mu.Lock()
+	defer a.store.mu.Unlock()
+
+	if indexOf(a.store.audits, func(audit model.AuditLog) bool { return audit.Id == payload.Id }) >= 0 {
+		return uniqueViolation("audit_log_pkey")
+	}
+	// salinan supaya perubahan slice dari pemanggil tidak ikut mengubah data yang tersimpan
+	payload.Before = copyJSON(payload.Before)
+	payload.After = copyJSON(payload.After)
+	a.store.audits = append(a.store.audits, payload)
+	return nil
+}
+
+// Paging implements AuditRepository.
+func (a *auditRepository) Paging(filter dto.AuditFilterDto) ([]model.AuditLog, dto.Paging, error) {
+	a.store.mu.RLock()
+	defer a.store.mu.RUnlock()
+
+	var audits []model.AuditLog
+	for _, audit := range a.store.audits {
+		if matchAuditFilter(audit, filter) {
+			audits = append(audits, audit)
+		}
+	}
+	sort.SliceStable(audits, func(i, j int) bool {
+		if !audits[i].CreatedAt.Equal(audits[j].CreatedAt) {
+			return audits[i].CreatedAt.After(audits[j].CreatedAt)
+		}
+		return audits[i].Id < audits[j].Id
+	})
+
+	result, paging := paginate(audits, dto.PaginationParam{Page: filter.Page, Limit: filter.Limit})
+	return result, paging, nil
+}
+
+// filter sama dengan auditPagingFilter pada repository postgres, tanggal "to" inklusif
+func matchAuditFilter(audit model.AuditLog, filter dto.AuditFilterDto) bool {
+	if filter.EntityType != "" && audit.EntityType != filter.EntityType {
+		return false
+	}
+	if filter.EntityId != "" && audit.EntityId != filter.EntityId {
+		return false
+	}
+	if filter.Actor != "" && audit.Actor != filter.Actor {
+		return false
+	}
+	if !filter.DateFrom.IsZero() && dateText(audit.CreatedAt) < dateText(filter.DateFrom) {
+		return false
+	}
+	if !filter.DateTo.IsZero() && dateText(audit.CreatedAt) > dateText(filter.DateTo) {
+		return false
+	}
+	return true
+}
+
+func copyJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	return append(json.RawMessage(nil), data...)
+}
+
+func NewAuditRepository(store *Store) repository.AuditRepository {
+	return &auditRepository{store: store}
+}
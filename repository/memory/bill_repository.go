@@ -0,0 +1,430 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type billRepository struct {
+	store *Store
+}
+
+// Create implements BillRepository.
+func (b *billRepository) Create(payload model.Bill) error {
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+
+	if indexOf(b.store.bills, func(bill billRow) bool { return bill.Id == payload.Id }) >= 0 {
+		return uniqueViolation("bill_pkey")
+	}
+	if !b.store.hasEmployee(payload.EmployeeId) {
+		return foreignKeyViolation("bill", "bill_employee_id_fkey")
+	}
+	if !b.store.hasCustomer(payload.CustomerId) {
+		return foreignKeyViolation("bill", "bill_customer_id_fkey")
+	}
+	if err := b.store.checkBillDetailIds(payload.Id, payload.BillDetails); err != nil {
+		return err
+	}
+
+	// pembayaran dihitung selama lock dipegang, sama seperti di repository sql
+	var total, amountPaid int
+	for _, detail := range payload.BillDetails {
+		total += detail.ProductPrice * detail.Qty
+	}
+	for _, payment := range b.store.payments {
+		if payment.BillId == payload.Id {
+			amountPaid += payment.Amount
+		}
+	}
+	if total < amountPaid {
+		return fmt.Errorf("%w: total %d, amount paid %d", exceptions.ErrBillTotalBelowAmountPaid, total, amountPaid)
+	}
+
+	bill := payload
+	bill.BillDate = dateOnly(payload.BillDate)
+	bill.BillDetails = nil
+	b.store.bills = append(b.store.bills, billRow{Bill: bill})
+	b.store.billDetails = append(b.store.billDetails, payload.BillDetails...)
+	return nil
+}
+
+// Get implements BillRepository.
+func (b *billRepository) Get(id string) (dto.BillResponseDto, error) {
+	b.store.mu.RLock()
+	defer b.store.mu.RUnlock()
+
+	index := indexOf(b.store.bills, func(bill billRow) bool { return bill.Id == id })
+	if index < 0 {
+		return dto.BillResponseDto{}, sql.ErrNoRows
+	}
+	billResponseDto, ok := b.store.joinBill(b.store.bills[index])
+	if !ok {
+		return dto.BillResponseDto{}, sql.ErrNoRows
+	}
+
+	for _, detail := range b.store.billDetails {
+		if detail.BillId != id {
+			continue
+		}
+		products := b.store.joinProducts(func(product model.Product) bool { return product.Id == detail.ProductId })
+		if len(products) == 0 {
+			continue
+		}
+		product := products[0]
+		billResponseDto.BillDetails = append(billResponseDto.BillDetails, dto.BillDetailResponseDto{
+			Id:           detail.Id,
+			BillId:       detail.BillId,
+			Product:      model.Product{Id: product.Id, Name: product.Name, Price: product.Price, Uom: product.Uom},
+			ProductPrice: detail.ProductPrice,
+			Qty:          detail.Qty,
+		})
+	}
+	return billResponseDto, nil
+}
+
+// Paging implements BillRepository.
+func (b *billRepository) Paging(requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error) {
+	compare, err := billPagingOrder(requestPaging)
+	if err != nil {
+		return nil, dto.Paging{}, err
+	}
+
+	b.store.mu.RLock()
+	defer b.store.mu.RUnlock()
+
+	var bills []dto.BillResponseDto
+	for _, row := range b.store.bills {
+		if !matchBillFilter(row, requestPaging) {
+			continue
+		}
+		bill, ok := b.store.joinBill(row)
+		if !ok {
+			continue
+		}
+		bill.TotalBill = b.store.billTotal(row.Id)
+		bills = append(bills, bill)
+	}
+	sort.SliceStable(bills, func(i, j int) bool {
+		return compare(bills[i], bills[j])
+	})
+
+	result, paging := paginate(bills, requestPaging)
+	return result, paging, nil
+}
+
+// UpdateStatus implements BillRepository.
+func (b *billRepository) UpdateStatus(payload model.BillStatusHistory) error {
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+
+	// hanya update kalau status masih sama, supaya transisi tidak saling menimpa
+	index := indexOf(b.store.bills, func(bill billRow) bool { return bill.Id == payload.BillId && bill.Status == payload.FromStatus })
+	if index < 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.FromStatus)
+	}
+	b.store.bills[index].Status = payload.ToStatus
+	b.store.billHistories = append(b.store.billHistories, payload)
+	return nil
+}
+
+// ListStatusHistory implements BillRepository.
+func (b *billRepository) ListStatusHistory(billId string) ([]model.BillStatusHistory, error) {
+	b.store.mu.RLock()
+	defer b.store.mu.RUnlock()
+
+	histories := filter(b.store.billHistories, func(history model.BillStatusHistory) bool { return history.BillId == billId })
+	sort.SliceStable(histories, func(i, j int) bool {
+		return histories[i].ChangedAt.Before(histories[j].ChangedAt)
+	})
+	return histories, nil
+}
+
+// Void implements BillRepository.
+func (b *billRepository) Void(payload model.BillVoid) error {
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+
+	index := indexOf(b.store.bills, func(bill billRow) bool { return bill.Id == payload.BillId && bill.Status == payload.FromStatus })
+	if index < 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.FromStatus)
+	}
+	bill := &b.store.bills[index]
+	bill.Status = model.BillStatusCancelled
+	bill.VoidReason = payload.Reason
+	bill.VoidedBy = payload.VoidedBy
+	voidedAt := payload.VoidedAt
+	bill.VoidedAt = &voidedAt
+
+	b.store.billHistories = append(b.store.billHistories, model.BillStatusHistory{
+		Id:         common.GenerateID(),
+		BillId:     payload.BillId,
+		FromStatus: payload.FromStatus,
+		ToStatus:   model.BillStatusCancelled,
+		ChangedBy:  payload.VoidedBy,
+		ChangedAt:  payload.VoidedAt,
+	})
+
+	// setiap pembayaran dibuatkan baris pembalik dengan nominal negatif, data aslinya tidak dihapus
+	for _, payment := range filter(b.store.payments, func(payment model.Payment) bool {
+		return payment.BillId == payload.BillId && payment.ReversalOf == ""
+	}) {
+		b.store.payments = append(b.store.payments, model.Payment{
+			Id:          common.GenerateID(),
+			BillId:      payload.BillId,
+			Amount:      -payment.Amount,
+			Method:      payment.Method,
+			PaymentDate: payload.VoidedAt,
+			ReceivedBy:  payload.VoidedBy,
+			ReversalOf:  payment.Id,
+		})
+	}
+	return nil
+}
+
+// UpdateDetails implements BillRepository.
+func (b *billRepository) UpdateDetails(payload model.Bill) error {
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+
+	// status di cek lagi supaya bill yang barusan selesai / di void tidak ikut berubah
+	index := indexOf(b.store.bills, func(bill billRow) bool { return bill.Id == payload.Id && bill.Status == payload.Status })
+	if index < 0 {
+		return fmt.Errorf("%w: bill status is no longer %s", exceptions.ErrBillStatusConflict, payload.Status)
+	}
+	if err := b.store.checkBillDetailIds(payload.Id, payload.BillDetails); err != nil {
+		return err
+	}
+
+	// pembayaran dihitung selama lock dipegang, sama seperti di repository sql
+	var total, amountPaid int
+	for _, detail := range payload.BillDetails {
+		total += detail.ProductPrice * detail.Qty
+	}
+	for _, payment := range b.store.payments {
+		if payment.BillId == payload.Id {
+			amountPaid += payment.Amount
+		}
+	}
+	if total < amountPaid {
+		return fmt.Errorf("%w: total %d, amount paid %d", exceptions.ErrBillTotalBelowAmountPaid, total, amountPaid)
+	}
+
+	b.store.bills[index].FinishDate = payload.FinishDate
+	b.store.billDetails = append(filter(b.store.billDetails, func(detail model.BillDetail) bool {
+		return detail.BillId != payload.Id
+	}), payload.BillDetails...)
+	return nil
+}
+
+// GetCustomerSummary implements BillRepository.
+func (b *billRepository) GetCustomerSummary(customerId string) (dto.CustomerSummaryDto, error) {
+	b.store.mu.RLock()
+	defer b.store.mu.RUnlock()
+
+	// bill yang di void tidak dihitung sebagai transaksi customer
+	var summary dto.CustomerSummaryDto
+	billIds := make(map[string]bool)
+	for _, bill := range b.store.bills {
+		if bill.CustomerId != customerId || bill.Status == model.BillStatusCancelled {
+			continue
+		}
+		billIds[bill.Id] = true
+		summary.TotalOrders++
+		summary.TotalSpent += b.store.billTotal(bill.Id)
+		if summary.LastVisit == nil || bill.EntryDate.After(*summary.LastVisit) {
+			entryDate := bill.EntryDate
+			summary.LastVisit = &entryDate
+		}
+	}
+
+	var favourites []dto.FavouriteProductDto
+	orders := make(map[string]map[string]bool)
+	for _, detail := range b.store.billDetails {
+		if !billIds[detail.BillId] {
+			continue
+		}
+		index := indexOf(favourites, func(favourite dto.FavouriteProductDto) bool { return favourite.Product.Id == detail.ProductId })
+		if index < 0 {
+			products := b.store.joinProducts(func(product model.Product) bool { return product.Id == detail.ProductId })
+			if len(products) == 0 {
+				continue
+			}
+			product := products[0]
+			product.Version, product.DeletedAt = 0, nil
+			favourites = append(favourites, dto.FavouriteProductDto{Product: product})
+			orders[detail.ProductId] = make(map[string]bool)
+			index = len(favourites) - 1
+		}
+		favourites[index].TotalQty += detail.Qty
+		orders[detail.ProductId][detail.BillId] = true
+	}
+	for i := range favourites {
+		favourites[i].OrderCount = len(orders[favourites[i].Product.Id])
+	}
+	sort.SliceStable(favourites, func(i, j int) bool {
+		if favourites[i].OrderCount != favourites[j].OrderCount {
+			return favourites[i].OrderCount > favourites[j].OrderCount
+		}
+		return favourites[i].TotalQty > favourites[j].TotalQty
+	})
+	if len(favourites) > 5 {
+		favourites = favourites[:5]
+	}
+	summary.FavouriteProducts = favourites
+	return summary, nil
+}
+
+// joinBill sama dengan bill JOIN customer JOIN employee beserta total pembayaran
+func (s *Store) joinBill(row billRow) (dto.BillResponseDto, bool) {
+	customerIndex := indexOf(s.customers, func(customer model.Customer) bool { return customer.Id == row.CustomerId })
+	employeeIndex := indexOf(s.employees, func(employee model.Employee) bool { return employee.Id == row.EmployeeId })
+	if customerIndex < 0 || employeeIndex < 0 {
+		return dto.BillResponseDto{}, false
+	}
+	customer := s.customers[customerIndex]
+	employee := s.employees[employeeIndex]
+
+	bill := dto.BillResponseDto{
+		Id:         row.Id,
+		BillDate:   row.BillDate,
+		EntryDate:  row.EntryDate,
+		FinishDate: row.FinishDate,
+		Status:     row.Status,
+		Customer:   model.Customer{Id: customer.Id, Name: customer.Name, PhoneNumber: customer.PhoneNumber, Address: customer.Address},
+		Employee:   model.Employee{Id: employee.Id, Name: employee.Name, PhoneNumber: employee.PhoneNumber, Address: employee.Address},
+		VoidReason: row.VoidReason,
+		VoidedBy:   row.VoidedBy,
+		VoidedAt:   row.VoidedAt,
+	}
+	for _, payment := range s.payments {
+		if payment.BillId == row.Id {
+			bill.AmountPaid += payment.Amount
+		}
+	}
+	return bill, true
+}
+
+func (s *Store) billTotal(billId string) int {
+	var total int
+	for _, detail := range s.billDetails {
+		if detail.BillId == billId {
+			total += detail.ProductPrice * detail.Qty
+		}
+	}
+	return total
+}
+
+// checkBillDetailIds memastikan id detail tidak dipakai oleh bill lain maupun dobel di payload
+func (s *Store) checkBillDetailIds(billId string, details []model.BillDetail) error {
+	ids := make(map[string]bool, len(details))
+	for _, detail := range details {
+		if ids[detail.Id] || indexOf(s.billDetails, func(existing model.BillDetail) bool {
+			return existing.Id == detail.Id && existing.BillId != billId
+		}) >= 0 {
+			return uniqueViolation("bill_detail_pkey")
+		}
+		ids[detail.Id] = true
+	}
+	return nil
+}
+
+func (s *Store) hasCustomer(id string) bool {
+	return indexOf(s.customers, func(customer model.Customer) bool { return customer.Id == id }) >= 0
+}
+
+func (s *Store) hasEmployee(id string) bool {
+	return indexOf(s.employees, func(employee model.Employee) bool { return employee.Id == id }) >= 0
+}
+
+// filter sama dengan billPagingFilter pada repository postgres, tanggal "to" inklusif
+func matchBillFilter(bill billRow, requestPaging dto.PaginationParam) bool {
+	if requestPaging.Status != "" && bill.Status != requestPaging.Status {
+		return false
+	}
+	if requestPaging.CustomerId != "" && bill.CustomerId != requestPaging.CustomerId {
+		return false
+	}
+	if requestPaging.EmployeeId != "" && bill.EmployeeId != requestPaging.EmployeeId {
+		return false
+	}
+	if !requestPaging.BillDateFrom.IsZero() && dateText(bill.BillDate) < dateText(requestPaging.BillDateFrom) {
+		return false
+	}
+	if !requestPaging.BillDateTo.IsZero() && dateText(bill.BillDate) > dateText(requestPaging.BillDateTo) {
+		return false
+	}
+	if !requestPaging.FinishDateFrom.IsZero() && dateText(bill.FinishDate) < dateText(requestPaging.FinishDateFrom) {
+		return false
+	}
+	if !requestPaging.FinishDateTo.IsZero() && dateText(bill.FinishDate) > dateText(requestPaging.FinishDateTo) {
+		return false
+	}
+	return true
+}
+
+// field yang boleh dipakai untuk sort listing bill, sama dengan whitelist pada repository postgres
+var billSortFields = map[string]func(a dto.BillResponseDto, b dto.BillResponseDto) int{
+	"billDate":     func(a, b dto.BillResponseDto) int { return compareTime(a.BillDate, b.BillDate) },
+	"entryDate":    func(a, b dto.BillResponseDto) int { return compareTime(a.EntryDate, b.EntryDate) },
+	"finishDate":   func(a, b dto.BillResponseDto) int { return compareTime(a.FinishDate, b.FinishDate) },
+	"status":       func(a, b dto.BillResponseDto) int { return strings.Compare(a.Status, b.Status) },
+	"customerName": func(a, b dto.BillResponseDto) int { return strings.Compare(a.Customer.Name, b.Customer.Name) },
+	"employeeName": func(a, b dto.BillResponseDto) int { return strings.Compare(a.Employee.Name, b.Employee.Name) },
+	"totalBill":    func(a, b dto.BillResponseDto) int { return a.TotalBill - b.TotalBill },
+}
+
+// billPagingOrder mengembalikan fungsi less, id dipakai sebagai pengurut kedua supaya hasil paging stabil
+func billPagingOrder(requestPaging dto.PaginationParam) (func(a dto.BillResponseDto, b dto.BillResponseDto) bool, error) {
+	compare := billSortFields["billDate"]
+	if requestPaging.SortBy != "" {
+		var ok bool
+		compare, ok = billSortFields[requestPaging.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("%w field %s", exceptions.ErrInvalidSort, requestPaging.SortBy)
+		}
+	}
+
+	descending := true
+	switch strings.ToLower(requestPaging.SortOrder) {
+	case "", "desc":
+	case "asc":
+		descending = false
+	default:
+		return nil, fmt.Errorf("%w order %s", exceptions.ErrInvalidSort, requestPaging.SortOrder)
+	}
+
+	return func(a, b dto.BillResponseDto) bool {
+		result := compare(a, b)
+		if descending {
+			result = -result
+		}
+		if result != 0 {
+			return result < 0
+		}
+		return a.Id < b.Id
+	}, nil
+}
+
+func compareTime(a time.Time, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func NewBillRepository(store *Store) repository.BillRepository {
+	return &billRepository{store: store}
+}
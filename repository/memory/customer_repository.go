@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"database/sql"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type customerRepository struct {
+	store *Store
+}
+
+// Create implements CustomerRepository.
+func (c *customerRepository) Create(payload model.Customer) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if indexOf(c.store.customers, func(customer model.Customer) bool { return customer.Id == payload.Id }) >= 0 {
+		return uniqueViolation("customer_pkey")
+	}
+	// phone_number unique juga berlaku untuk customer yang sudah di soft delete
+	if indexOf(c.store.customers, func(customer model.Customer) bool { return customer.PhoneNumber == payload.PhoneNumber }) >= 0 {
+		return uniqueViolation("customer_phone_number_key")
+	}
+	c.store.customers = append(c.store.customers, model.Customer{
+		Id:          payload.Id,
+		Name:        payload.Name,
+		PhoneNumber: payload.PhoneNumber,
+		Address:     payload.Address,
+		Version:     1,
+	})
+	return nil
+}
+
+// Delete implements CustomerRepository.
+func (c *customerRepository) Delete(id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	index := indexOf(c.store.customers, func(customer model.Customer) bool { return customer.Id == id && customer.DeletedAt == nil })
+	if index >= 0 {
+		c.store.customers[index].DeletedAt = now()
+	}
+	return nil
+}
+
+// Get implements CustomerRepository.
+func (c *customerRepository) Get(id string) (model.Customer, error) {
+	return c.get(id, false)
+}
+
+// GetIncludeDeleted implements CustomerRepository.
+func (c *customerRepository) GetIncludeDeleted(id string) (model.Customer, error) {
+	return c.get(id, true)
+}
+
+func (c *customerRepository) get(id string, includeDeleted bool) (model.Customer, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	index := indexOf(c.store.customers, func(customer model.Customer) bool {
+		return customer.Id == id && (includeDeleted || customer.DeletedAt == nil)
+	})
+	if index < 0 {
+		return model.Customer{}, sql.ErrNoRows
+	}
+	return c.store.customers[index], nil
+}
+
+// Restore implements CustomerRepository.
+func (c *customerRepository) Restore(id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	index := indexOf(c.store.customers, func(customer model.Customer) bool { return customer.Id == id })
+	if index >= 0 {
+		c.store.customers[index].DeletedAt = nil
+	}
+	return nil
+}
+
+// GetPhoneNumber implements CustomerRepository.
+func (c *customerRepository) GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Customer, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	index := indexOf(c.store.customers, func(customer model.Customer) bool {
+		return customer.PhoneNumber == phoneNumber && (includeDeleted || customer.DeletedAt == nil)
+	})
+	if index < 0 {
+		return model.Customer{}, sql.ErrNoRows
+	}
+	customer := c.store.customers[index]
+	return model.Customer{Id: customer.Id, Name: customer.Name, PhoneNumber: customer.PhoneNumber, Address: customer.Address, DeletedAt: customer.DeletedAt}, nil
+}
+
+// List implements CustomerRepository.
+func (c *customerRepository) List() ([]model.Customer, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	return filter(c.store.customers, func(customer model.Customer) bool { return customer.DeletedAt == nil }), nil
+}
+
+// Paging implements CustomerRepository.
+func (c *customerRepository) Paging(requestPaging dto.PaginationParam) ([]model.Customer, dto.Paging, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	customers := filter(c.store.customers, func(customer model.Customer) bool {
+		return requestPaging.IncludeDeleted || customer.DeletedAt == nil
+	})
+	result, paging := paginate(customers, requestPaging)
+	return result, paging, nil
+}
+
+// Update implements CustomerRepository.
+func (c *customerRepository) Update(payload model.Customer) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	index := indexOf(c.store.customers, func(customer model.Customer) bool {
+		return customer.Id == payload.Id && customer.Version == payload.Version && customer.DeletedAt == nil
+	})
+	if index < 0 {
+		return exceptions.ErrVersionConflict
+	}
+	if indexOf(c.store.customers, func(customer model.Customer) bool {
+		return customer.Id != payload.Id && customer.PhoneNumber == payload.PhoneNumber
+	}) >= 0 {
+		return uniqueViolation("customer_phone_number_key")
+	}
+	customer := &c.store.customers[index]
+	customer.Name = payload.Name
+	customer.PhoneNumber = payload.PhoneNumber
+	customer.Address = payload.Address
+	customer.Version++
+	return nil
+}
+
+func NewCustomerRepository(store *Store) repository.CustomerRepository {
+	return &customerRepository{store: store}
+}
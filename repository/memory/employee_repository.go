@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"database/sql"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type employeeRepository struct {
+	store *Store
+}
+
+// Create implements EmployeeRepository.
+func (e *employeeRepository) Create(payload model.Employee) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if indexOf(e.store.employees, func(employee model.Employee) bool { return employee.Id == payload.Id }) >= 0 {
+		return uniqueViolation("employee_pkey")
+	}
+	// phone_number unique juga berlaku untuk employee yang sudah di soft delete
+	if indexOf(e.store.employees, func(employee model.Employee) bool { return employee.PhoneNumber == payload.PhoneNumber }) >= 0 {
+		return uniqueViolation("employee_phone_number_key")
+	}
+	e.store.employees = append(e.store.employees, model.Employee{
+		Id:          payload.Id,
+		Name:        payload.Name,
+		PhoneNumber: payload.PhoneNumber,
+		Address:     payload.Address,
+		Version:     1,
+	})
+	return nil
+}
+
+// Delete implements EmployeeRepository.
+func (e *employeeRepository) Delete(id string) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	index := indexOf(e.store.employees, func(employee model.Employee) bool { return employee.Id == id && employee.DeletedAt == nil })
+	if index >= 0 {
+		e.store.employees[index].DeletedAt = now()
+	}
+	return nil
+}
+
+// Get implements EmployeeRepository.
+func (e *employeeRepository) Get(id string) (model.Employee, error) {
+	return e.get(id, false)
+}
+
+// GetIncludeDeleted implements EmployeeRepository.
+func (e *employeeRepository) GetIncludeDeleted(id string) (model.Employee, error) {
+	return e.get(id, true)
+}
+
+func (e *employeeRepository) get(id string, includeDeleted bool) (model.Employee, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	index := indexOf(e.store.employees, func(employee model.Employee) bool {
+		return employee.Id == id && (includeDeleted || employee.DeletedAt == nil)
+	})
+	if index < 0 {
+		return model.Employee{}, sql.ErrNoRows
+	}
+	return e.store.employees[index], nil
+}
+
+// Restore implements EmployeeRepository.
+func (e *employeeRepository) Restore(id string) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	index := indexOf(e.store.employees, func(employee model.Employee) bool { return employee.Id == id })
+	if index >= 0 {
+		e.store.employees[index].DeletedAt = nil
+	}
+	return nil
+}
+
+// GetPhoneNumber implements EmployeeRepository.
+func (e *employeeRepository) GetPhoneNumber(phoneNumber string, includeDeleted bool) (model.Employee, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	index := indexOf(e.store.employees, func(employee model.Employee) bool {
+		return employee.PhoneNumber == phoneNumber && (includeDeleted || employee.DeletedAt == nil)
+	})
+	if index < 0 {
+		return model.Employee{}, sql.ErrNoRows
+	}
+	employee := e.store.employees[index]
+	return model.Employee{Id: employee.Id, Name: employee.Name, PhoneNumber: employee.PhoneNumber, Address: employee.Address, DeletedAt: employee.DeletedAt}, nil
+}
+
+// List implements EmployeeRepository.
+func (e *employeeRepository) List() ([]model.Employee, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	return filter(e.store.employees, func(employee model.Employee) bool { return employee.DeletedAt == nil }), nil
+}
+
+// Paging implements EmployeeRepository.
+func (e *employeeRepository) Paging(requestPaging dto.PaginationParam) ([]model.Employee, dto.Paging, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	employees := filter(e.store.employees, func(employee model.Employee) bool {
+		return requestPaging.IncludeDeleted || employee.DeletedAt == nil
+	})
+	result, paging := paginate(employees, requestPaging)
+	return result, paging, nil
+}
+
+// Update implements EmployeeRepository.
+func (e *employeeRepository) Update(payload model.Employee) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	index := indexOf(e.store.employees, func(employee model.Employee) bool {
+		return employee.Id == payload.Id && employee.Version == payload.Version && employee.DeletedAt == nil
+	})
+	if index < 0 {
+		return exceptions.ErrVersionConflict
+	}
+	if indexOf(e.store.employees, func(employee model.Employee) bool {
+		return employee.Id != payload.Id && employee.PhoneNumber == payload.PhoneNumber
+	}) >= 0 {
+		return uniqueViolation("employee_phone_number_key")
+	}
+	employee := &e.store.employees[index]
+	employee.Name = payload.Name
+	employee.PhoneNumber = payload.PhoneNumber
+	employee.Address = payload.Address
+	employee.Version++
+	return nil
+}
+
+func NewEmployeeRepository(store *Store) repository.EmployeeRepository {
+	return &employeeRepository{store: store}
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+type idempotencyRepository struct {
+	store *Store
+}
+
+// Create implements IdempotencyRepository.
+// mengembalikan false kalau key sudah dipakai, sehingga hanya satu request yang lanjut diproses
+func (i *idempotencyRepository) Create(payload model.IdempotencyKey) (bool, error) {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+
+	if i.store.idempotencyKeyIndex(payload.Username, payload.Key) >= 0 {
+		return false, nil
+	}
+	i.store.idempotencyKeys = append(i.store.idempotencyKeys, model.IdempotencyKey{
+		Username:    payload.Username,
+		Key:         payload.Key,
+		RequestHash: payload.RequestHash,
+		CreatedAt:   payload.CreatedAt,
+	})
+	return true, nil
+}
+
+// Get implements IdempotencyRepository.
+func (i *idempotencyRepository) Get(username string, key string) (model.IdempotencyKey, error) {
+	i.store.mu.RLock()
+	defer i.store.mu.RUnlock()
+
+	index := i.store.idempotencyKeyIndex(username, key)
+	if index < 0 {
+		return model.IdempotencyKey{}, sql.ErrNoRows
+	}
+	idempotencyKey := i.store.idempotencyKeys[index]
+	idempotencyKey.ResponseBody = append([]byte(nil), idempotencyKey.ResponseBody...)
+	return idempotencyKey, nil
+}
+
+// Complete implements IdempotencyRepository.
+func (i *idempotencyRepository) Complete(payload model.IdempotencyKey) error {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+
+	if index := i.store.idempotencyKeyIndex(payload.Username, payload.Key); index >= 0 {
+		idempotencyKey := &i.store.idempotencyKeys[index]
+		idempotencyKey.StatusCode = payload.StatusCode
+		idempotencyKey.ContentType = payload.ContentType
+		idempotencyKey.ResponseBody = append([]byte(nil), payload.ResponseBody...)
+	}
+	return nil
+}
+
+// Delete implements IdempotencyRepository.
+func (i *idempotencyRepository) Delete(username string, key string) error {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+
+	i.store.idempotencyKeys = filter(i.store.idempotencyKeys, func(idempotencyKey model.IdempotencyKey) bool {
+		return idempotencyKey.Username != username || idempotencyKey.Key != key
+	})
+	return nil
+}
+
+// DeleteExpired implements IdempotencyRepository.
+func (i *idempotencyRepository) DeleteExpired(before time.Time) error {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+
+	i.store.idempotencyKeys = filter(i.store.idempotencyKeys, func(idempotencyKey model.IdempotencyKey) bool {
+		return !idempotencyKey.CreatedAt.Before(before)
+	})
+	return nil
+}
+
+func (s *Store) idempotencyKeyIndex(username string, key string) int {
+	return indexOf(s.idempotencyKeys, func(idempotencyKey model.IdempotencyKey) bool {
+		return idempotencyKey.Username == username && idempotencyKey.Key == key
+	})
+}
+
+func NewIdempotencyRepository(store *Store) repository.IdempotencyRepository {
+	return &idempotencyRepository{store: store}
+}
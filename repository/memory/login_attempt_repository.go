@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+type loginAttemptRepository struct {
+	store *Store
+}
+
+// Get implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Get(kind string, value string) (model.LoginAttempt, error) {
+	l.store.mu.RLock()
+	defer l.store.mu.RUnlock()
+
+	index := l.store.loginAttemptIndex(kind, value)
+	if index < 0 {
+		return model.LoginAttempt{}, sql.ErrNoRows
+	}
+	return l.store.loginAttempts[index], nil
+}
+
+// RecordFailure implements LoginAttemptRepository.
+func (l *loginAttemptRepository) RecordFailure(kind string, value string, failedAt time.Time, windowStart time.Time) (int, error) {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	index := l.store.loginAttemptIndex(kind, value)
+	if index < 0 {
+		l.store.loginAttempts = append(l.store.loginAttempts, model.LoginAttempt{Kind: kind, Value: value, FailedCount: 1, LastFailedAt: failedAt})
+		return 1, nil
+	}
+
+	// hitungan mulai dari awal lagi kalau gagal terakhir sudah di luar window
+	attempt := &l.store.loginAttempts[index]
+	if attempt.LastFailedAt.Before(windowStart) {
+		attempt.FailedCount = 1
+	} else {
+		attempt.FailedCount++
+	}
+	attempt.LastFailedAt = failedAt
+	return attempt.FailedCount, nil
+}
+
+// Lock implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Lock(kind string, value string, lockedUntil time.Time) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	if index := l.store.loginAttemptIndex(kind, value); index >= 0 {
+		l.store.loginAttempts[index].LockedUntil = &lockedUntil
+	}
+	return nil
+}
+
+// Reset implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Reset(kind string, value string) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	l.store.resetLoginAttempt(kind, value)
+	return nil
+}
+
+// CreateLockout implements LoginAttemptRepository.
+func (l *loginAttemptRepository) CreateLockout(payload model.LoginLockout) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	if indexOf(l.store.loginLockouts, func(lockout model.LoginLockout) bool { return lockout.Id == payload.Id }) >= 0 {
+		return uniqueViolation("login_lockout_pkey")
+	}
+	payload.UnlockedBy = ""
+	payload.UnlockedAt = nil
+	l.store.loginLockouts = append(l.store.loginLockouts, payload)
+	return nil
+}
+
+// GetLockout implements LoginAttemptRepository.
+func (l *loginAttemptRepository) GetLockout(id string) (model.LoginLockout, error) {
+	l.store.mu.RLock()
+	defer l.store.mu.RUnlock()
+
+	index := indexOf(l.store.loginLockouts, func(lockout model.LoginLockout) bool { return lockout.Id == id })
+	if index < 0 {
+		return model.LoginLockout{}, sql.ErrNoRows
+	}
+	return l.store.loginLockouts[index], nil
+}
+
+// ListLockouts implements LoginAttemptRepository.
+func (l *loginAttemptRepository) ListLockouts(activeAt *time.Time) ([]model.LoginLockout, error) {
+	l.store.mu.RLock()
+	defer l.store.mu.RUnlock()
+
+	// activeAt diisi untuk hanya menampilkan lockout yang masih berlaku
+	lockouts := filter(l.store.loginLockouts, func(lockout model.LoginLockout) bool {
+		return activeAt == nil || (lockout.UnlockedAt == nil && lockout.LockedUntil.After(*activeAt))
+	})
+	sort.SliceStable(lockouts, func(i, j int) bool {
+		return lockouts[i].LockedAt.After(lockouts[j].LockedAt)
+	})
+	return lockouts, nil
+}
+
+// Unlock implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Unlock(id string, unlockedBy string, unlockedAt time.Time) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	index := indexOf(l.store.loginLockouts, func(lockout model.LoginLockout) bool { return lockout.Id == id })
+	if index < 0 {
+		return sql.ErrNoRows
+	}
+	lockout := &l.store.loginLockouts[index]
+	lockout.UnlockedBy = unlockedBy
+	lockout.UnlockedAt = &unlockedAt
+	l.store.resetLoginAttempt(lockout.Kind, lockout.Value)
+	return nil
+}
+
+func (s *Store) loginAttemptIndex(kind string, value string) int {
+	return indexOf(s.loginAttempts, func(attempt model.LoginAttempt) bool { return attempt.Kind == kind && attempt.Value == value })
+}
+
+func (s *Store) resetLoginAttempt(kind string, value string) {
+	s.loginAttempts = filter(s.loginAttempts, func(attempt model.LoginAttempt) bool { return attempt.Kind != kind || attempt.Value != value })
+}
+
+func NewLoginAttemptRepository(store *Store) repository.LoginAttemptRepository {
+	return &loginAttemptRepository{store: store}
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type paymentRepository struct {
+	store *Store
+}
+
+// Create implements PaymentRepository.
+func (p *paymentRepository) Create(payload model.Payment) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if indexOf(p.store.payments, func(payment model.Payment) bool { return payment.Id == payload.Id }) >= 0 {
+		return uniqueViolation("payment_pkey")
+	}
+	billIndex := indexOf(p.store.bills, func(bill billRow) bool { return bill.Id == payload.BillId })
+	if billIndex < 0 {
+		return foreignKeyViolation("payment", "payment_bill_id_fkey")
+	}
+	if payload.ReversalOf != "" && indexOf(p.store.payments, func(payment model.Payment) bool { return payment.Id == payload.ReversalOf }) < 0 {
+		return foreignKeyViolation("payment", "payment_reversal_of_fkey")
+	}
+	// cek sisa tagihan dilakukan selama lock dipegang, sama seperti baris bill yang dikunci di repository sql
+	if p.store.bills[billIndex].Status == model.BillStatusCancelled {
+		return fmt.Errorf("bill with ID %s is cancelled", payload.BillId)
+	}
+	balanceDue := 0
+	for _, detail := range p.store.billDetails {
+		if detail.BillId == payload.BillId {
+			balanceDue += detail.ProductPrice * detail.Qty
+		}
+	}
+	for _, payment := range p.store.payments {
+		if payment.BillId == payload.BillId {
+			balanceDue -= payment.Amount
+		}
+	}
+	if payload.Amount > balanceDue {
+		return fmt.Errorf("%w: amount %d, balance due %d", exceptions.ErrPaymentExceedsBalance, payload.Amount, balanceDue)
+	}
+	p.store.payments = append(p.store.payments, payload)
+	return nil
+}
+
+// ListByBillId implements PaymentRepository.
+func (p *paymentRepository) ListByBillId(billId string) ([]model.Payment, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	payments := filter(p.store.payments, func(payment model.Payment) bool { return payment.BillId == billId })
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].PaymentDate.Before(payments[j].PaymentDate)
+	})
+	return payments, nil
+}
+
+func NewPaymentRepository(store *Store) repository.PaymentRepository {
+	return &paymentRepository{store: store}
+}
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"database/sql"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type productRepository struct {
+	store *Store
+}
+
+func (p *productRepository) Create(payload model.Product) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if indexOf(p.store.products, func(product model.Product) bool { return product.Id == payload.Id }) >= 0 {
+		return uniqueViolation("product_pkey")
+	}
+	if !p.store.hasUom(payload.Uom.Id) {
+		return foreignKeyViolation("product", "product_uom_id_fkey")
+	}
+	p.store.products = append(p.store.products, model.Product{
+		Id:              payload.Id,
+		Name:            payload.Name,
+		Price:           payload.Price,
+		TurnaroundHours: payload.TurnaroundHours,
+		Uom:             model.Uom{Id: payload.Uom.Id},
+		Version:         1,
+	})
+	return nil
+}
+
+func (p *productRepository) List() ([]model.Product, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	return p.store.joinProducts(func(product model.Product) bool { return product.DeletedAt == nil }), nil
+}
+
+func (p *productRepository) Get(id string) (model.Product, error) {
+	return p.get(id, false)
+}
+
+func (p *productRepository) GetIncludeDeleted(id string) (model.Product, error) {
+	return p.get(id, true)
+}
+
+func (p *productRepository) get(id string, includeDeleted bool) (model.Product, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	products := p.store.joinProducts(func(product model.Product) bool {
+		return product.Id == id && (includeDeleted || product.DeletedAt == nil)
+	})
+	if len(products) == 0 {
+		return model.Product{}, sql.ErrNoRows
+	}
+	return products[0], nil
+}
+
+func (p *productRepository) Update(payload model.Product) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	index := indexOf(p.store.products, func(product model.Product) bool {
+		return product.Id == payload.Id && product.Version == payload.Version && product.DeletedAt == nil
+	})
+	if index < 0 {
+		return exceptions.ErrVersionConflict
+	}
+	if !p.store.hasUom(payload.Uom.Id) {
+		return foreignKeyViolation("product", "product_uom_id_fkey")
+	}
+	product := &p.store.products[index]
+	product.Name = payload.Name
+	product.Price = payload.Price
+	product.Uom = model.Uom{Id: payload.Uom.Id}
+	product.TurnaroundHours = payload.TurnaroundHours
+	product.Version++
+	return nil
+}
+
+func (p *productRepository) Delete(id string) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	index := indexOf(p.store.products, func(product model.Product) bool { return product.Id == id && product.DeletedAt == nil })
+	if index >= 0 {
+		p.store.products[index].DeletedAt = now()
+	}
+	return nil
+}
+
+func (p *productRepository) Restore(id string) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	index := indexOf(p.store.products, func(product model.Product) bool { return product.Id == id })
+	if index >= 0 {
+		p.store.products[index].DeletedAt = nil
+	}
+	return nil
+}
+
+func (p *productRepository) Paging(requestPaging dto.PaginationParam) ([]model.Product, dto.Paging, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	products := p.store.joinProducts(func(product model.Product) bool {
+		return requestPaging.IncludeDeleted || product.DeletedAt == nil
+	})
+	result, paging := paginate(products, requestPaging)
+	return result, paging, nil
+}
+
+// joinProducts sama dengan product INNER JOIN uom, nama uom selalu diambil dari tabel uom
+func (s *Store) joinProducts(match func(model.Product) bool) []model.Product {
+	var products []model.Product
+	for _, product := range s.products {
+		if !match(product) {
+			continue
+		}
+		uomIndex := indexOf(s.uoms, func(uom model.Uom) bool { return uom.Id == product.Uom.Id })
+		if uomIndex < 0 {
+			continue
+		}
+		product.Uom = model.Uom{Id: s.uoms[uomIndex].Id, Name: s.uoms[uomIndex].Name}
+		products = append(products, product)
+	}
+	return products
+}
+
+// uom yang sudah di soft delete tetap ada di tabel, jadi masih memenuhi foreign key
+func (s *Store) hasUom(id string) bool {
+	return indexOf(s.uoms, func(uom model.Uom) bool { return uom.Id == id }) >= 0
+}
+
+func NewProductRepository(store *Store) repository.ProductRepository {
+	return &productRepository{store: store}
+}
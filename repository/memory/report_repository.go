@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+type reportRepository struct {
+	store *Store
+}
+
+// ListDailyRevenue implements ReportRepository.
+func (r *reportRepository) ListDailyRevenue(from time.Time, to time.Time) ([]dto.RevenueRowDto, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var revenues []dto.RevenueRowDto
+	for _, bill := range r.store.reportBills(from, to) {
+		for _, detail := range r.store.billDetails {
+			if detail.BillId != bill.Id {
+				continue
+			}
+			products := r.store.joinProducts(func(product model.Product) bool { return product.Id == detail.ProductId })
+			if len(products) == 0 {
+				continue
+			}
+			product := products[0]
+
+			// dikelompokkan per tanggal bill dan produk
+			index := indexOf(revenues, func(revenue dto.RevenueRowDto) bool {
+				return revenue.BillDate.Equal(bill.BillDate) && revenue.ProductId == product.Id
+			})
+			if index < 0 {
+				revenues = append(revenues, dto.RevenueRowDto{
+					BillDate:    bill.BillDate,
+					ProductId:   product.Id,
+					ProductName: product.Name,
+					UomId:       product.Uom.Id,
+					UomName:     product.Uom.Name,
+				})
+				index = len(revenues) - 1
+			}
+			revenues[index].Qty += detail.Qty
+			revenues[index].Revenue += detail.ProductPrice * detail.Qty
+		}
+	}
+	sort.SliceStable(revenues, func(i, j int) bool {
+		if !revenues[i].BillDate.Equal(revenues[j].BillDate) {
+			return revenues[i].BillDate.Before(revenues[j].BillDate)
+		}
+		return revenues[i].ProductName < revenues[j].ProductName
+	})
+	return revenues, nil
+}
+
+// ListDailyOrderCount implements ReportRepository.
+func (r *reportRepository) ListDailyOrderCount(from time.Time, to time.Time) ([]dto.OrderCountRowDto, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orderCounts []dto.OrderCountRowDto
+	for _, bill := range r.store.reportBills(from, to) {
+		index := indexOf(orderCounts, func(orderCount dto.OrderCountRowDto) bool { return orderCount.BillDate.Equal(bill.BillDate) })
+		if index < 0 {
+			orderCounts = append(orderCounts, dto.OrderCountRowDto{BillDate: bill.BillDate})
+			index = len(orderCounts) - 1
+		}
+		orderCounts[index].Orders++
+	}
+	sort.SliceStable(orderCounts, func(i, j int) bool {
+		return orderCounts[i].BillDate.Before(orderCounts[j].BillDate)
+	})
+	return orderCounts, nil
+}
+
+// ListEmployeeBills implements ReportRepository.
+func (r *reportRepository) ListEmployeeBills(from time.Time, to time.Time) ([]dto.EmployeeBillRowDto, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var employeeBills []dto.EmployeeBillRowDto
+	for _, bill := range r.store.reportBills(from, to) {
+		employeeBill := dto.EmployeeBillRowDto{
+			EmployeeId: bill.EmployeeId,
+			EntryDate:  bill.EntryDate,
+			FinishDate: bill.FinishDate,
+			Total:      r.store.billTotal(bill.Id),
+		}
+		// ready_at adalah kapan bill pertama kali selesai dikerjakan
+		for _, history := range r.store.billHistories {
+			if history.BillId != bill.Id || history.ToStatus != model.BillStatusReady {
+				continue
+			}
+			if employeeBill.ReadyAt == nil || history.ChangedAt.Before(*employeeBill.ReadyAt) {
+				readyAt := history.ChangedAt
+				employeeBill.ReadyAt = &readyAt
+			}
+		}
+		employeeBills = append(employeeBills, employeeBill)
+	}
+	return employeeBills, nil
+}
+
+// reportBills mengambil bill yang tidak di void dengan tanggal bill di antara from dan to (inklusif)
+func (s *Store) reportBills(from time.Time, to time.Time) []billRow {
+	return filter(s.bills, func(bill billRow) bool {
+		return dateText(bill.BillDate) >= dateText(from) && dateText(bill.BillDate) <= dateText(to) && bill.Status != model.BillStatusCancelled
+	})
+}
+
+func NewReportRepository(store *Store) repository.ReportRepository {
+	return &reportRepository{store: store}
+}
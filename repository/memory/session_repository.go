@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+type sessionRepository struct {
+	store *Store
+}
+
+// Create implements SessionRepository.
+func (s *sessionRepository) Create(session model.Session, refreshToken model.RefreshToken) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if indexOf(s.store.sessions, func(existing model.Session) bool { return existing.Id == session.Id }) >= 0 {
+		return uniqueViolation("user_session_pkey")
+	}
+	if indexOf(s.store.users, func(user model.UserCredential) bool { return user.Id == session.UserId }) < 0 {
+		return foreignKeyViolation("user_session", "user_session_user_id_fkey")
+	}
+	if refreshToken.SessionId != session.Id {
+		return foreignKeyViolation("refresh_token", "refresh_token_session_id_fkey")
+	}
+	if err := s.store.checkRefreshToken(refreshToken); err != nil {
+		return err
+	}
+	s.store.sessions = append(s.store.sessions, model.Session{Id: session.Id, UserId: session.UserId, CreatedAt: session.CreatedAt})
+	s.store.refreshTokens = append(s.store.refreshTokens, newRefreshToken(refreshToken))
+	return nil
+}
+
+// Get implements SessionRepository.
+func (s *sessionRepository) Get(id string) (model.Session, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	index := indexOf(s.store.sessions, func(session model.Session) bool { return session.Id == id })
+	if index < 0 {
+		return model.Session{}, sql.ErrNoRows
+	}
+	return s.store.sessions[index], nil
+}
+
+// GetRefreshToken implements SessionRepository.
+func (s *sessionRepository) GetRefreshToken(tokenHash string) (model.RefreshToken, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	index := indexOf(s.store.refreshTokens, func(token model.RefreshToken) bool { return token.TokenHash == tokenHash })
+	if index < 0 {
+		return model.RefreshToken{}, sql.ErrNoRows
+	}
+	return s.store.refreshTokens[index], nil
+}
+
+// Rotate implements SessionRepository.
+func (s *sessionRepository) Rotate(usedTokenId string, usedAt time.Time, newToken model.RefreshToken) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	// token lama hanya boleh dipakai sekali, kalau sudah terpakai berarti ada yang memakai ulang
+	index := indexOf(s.store.refreshTokens, func(token model.RefreshToken) bool { return token.Id == usedTokenId && token.UsedAt == nil })
+	if index < 0 {
+		return fmt.Errorf("refresh token has already been used")
+	}
+	if indexOf(s.store.sessions, func(session model.Session) bool { return session.Id == newToken.SessionId }) < 0 {
+		return foreignKeyViolation("refresh_token", "refresh_token_session_id_fkey")
+	}
+	if err := s.store.checkRefreshToken(newToken); err != nil {
+		return err
+	}
+	s.store.refreshTokens[index].UsedAt = &usedAt
+	s.store.refreshTokens = append(s.store.refreshTokens, newRefreshToken(newToken))
+	return nil
+}
+
+// Revoke implements SessionRepository.
+func (s *sessionRepository) Revoke(id string, revokedAt time.Time) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	for i, session := range s.store.sessions {
+		if session.Id == id && session.RevokedAt == nil {
+			s.store.sessions[i].RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+// RevokeByUser implements SessionRepository.
+func (s *sessionRepository) RevokeByUser(userId string, revokedAt time.Time) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	for i, session := range s.store.sessions {
+		if session.UserId == userId && session.RevokedAt == nil {
+			s.store.sessions[i].RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+// id dan token_hash refresh token harus unik
+func (s *Store) checkRefreshToken(refreshToken model.RefreshToken) error {
+	if indexOf(s.refreshTokens, func(token model.RefreshToken) bool { return token.Id == refreshToken.Id }) >= 0 {
+		return uniqueViolation("refresh_token_pkey")
+	}
+	if indexOf(s.refreshTokens, func(token model.RefreshToken) bool { return token.TokenHash == refreshToken.TokenHash }) >= 0 {
+		return uniqueViolation("refresh_token_token_hash_key")
+	}
+	return nil
+}
+
+// used_at tidak diisi saat insert
+func newRefreshToken(refreshToken model.RefreshToken) model.RefreshToken {
+	return model.RefreshToken{
+		Id:        refreshToken.Id,
+		SessionId: refreshToken.SessionId,
+		TokenHash: refreshToken.TokenHash,
+		ExpiresAt: refreshToken.ExpiresAt,
+	}
+}
+
+func NewSessionRepository(store *Store) repository.SessionRepository {
+	return &sessionRepository{store: store}
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+)
+
+// Store menyimpan semua tabel di memory. Satu Store dipakai bersama oleh semua repository
+// supaya join antar tabel (misalnya bill dengan customer dan product) tetap jalan.
+// Data disimpan dalam slice supaya urutan hasil sama dengan urutan insert.
+type Store struct {
+	mu sync.RWMutex
+
+	uoms            []model.Uom
+	products        []model.Product
+	customers       []model.Customer
+	employees       []model.Employee
+	bills           []billRow
+	billDetails     []model.BillDetail
+	billHistories   []model.BillStatusHistory
+	payments        []model.Payment
+	users           []model.UserCredential
+	sessions        []model.Session
+	refreshTokens   []model.RefreshToken
+	loginAttempts   []model.LoginAttempt
+	loginLockouts   []model.LoginLockout
+	audits          []model.AuditLog
+	idempotencyKeys []model.IdempotencyKey
+}
+
+// billRow adalah satu baris tabel bill, BillDetails disimpan terpisah di billDetails
+type billRow struct {
+	model.Bill
+	VoidReason string
+	VoidedBy   string
+	VoidedAt   *time.Time
+}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+// pesan error dibuat sama dengan pesan constraint dari postgres
+func uniqueViolation(constraint string) error {
+	return fmt.Errorf("duplicate key value violates unique constraint \"%s\"", constraint)
+}
+
+func foreignKeyViolation(table string, constraint string) error {
+	return fmt.Errorf("insert or update on table \"%s\" violates foreign key constraint \"%s\"", table, constraint)
+}
+
+func indexOf[T any](items []T, match func(T) bool) int {
+	for i, item := range items {
+		if match(item) {
+			return i
+		}
+	}
+	return -1
+}
+
+func filter[T any](items []T, match func(T) bool) []T {
+	var result []T
+	for _, item := range items {
+		if match(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// paginate memotong hasil sesuai LIMIT dan OFFSET dari PaginationParam
+func paginate[T any](items []T, requestPaging dto.PaginationParam) ([]T, dto.Paging) {
+	paginationQuery := common.GetPaginationParams(requestPaging)
+	start := paginationQuery.Skip
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + paginationQuery.Take
+	if end > len(items) {
+		end = len(items)
+	}
+
+	var result []T
+	result = append(result, items[start:end]...)
+	return result, common.Paginate(paginationQuery.Page, paginationQuery.Take, len(items))
+}
+
+// tanggal dibandingkan sebagai teks YYYY-MM-DD seperti filter pada repository postgres
+func dateText(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// dateOnly meniru kolom bertipe date yang hanya menyimpan tanggal
+func dateOnly(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func now() *time.Time {
+	current := time.Now()
+	return &current
+}
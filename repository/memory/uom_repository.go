@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type uomRepository struct {
+	store *Store
+}
+
+func (u *uomRepository) Create(payload model.Uom) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	if indexOf(u.store.uoms, func(uom model.Uom) bool { return uom.Id == payload.Id }) >= 0 {
+		return uniqueViolation("uom_pkey")
+	}
+	u.store.uoms = append(u.store.uoms, model.Uom{Id: payload.Id, Name: payload.Name, Version: 1})
+	return nil
+}
+
+func (u *uomRepository) List() ([]model.Uom, error) {
+	return u.list(false)
+}
+
+func (u *uomRepository) ListIncludeDeleted() ([]model.Uom, error) {
+	return u.list(true)
+}
+
+func (u *uomRepository) list(includeDeleted bool) ([]model.Uom, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	return filter(u.store.uoms, func(uom model.Uom) bool {
+		return includeDeleted || uom.DeletedAt == nil
+	}), nil
+}
+
+func (u *uomRepository) Get(id string) (model.Uom, error) {
+	return u.get(id, false)
+}
+
+func (u *uomRepository) GetIncludeDeleted(id string) (model.Uom, error) {
+	return u.get(id, true)
+}
+
+func (u *uomRepository) get(id string, includeDeleted bool) (model.Uom, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	index := indexOf(u.store.uoms, func(uom model.Uom) bool {
+		return uom.Id == id && (includeDeleted || uom.DeletedAt == nil)
+	})
+	if index < 0 {
+		return model.Uom{}, sql.ErrNoRows
+	}
+	return u.store.uoms[index], nil
+}
+
+// GetByName sama dengan ILIKE '%name%' pada postgres
+func (u *uomRepository) GetByName(name string) (model.Uom, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	index := indexOf(u.store.uoms, func(uom model.Uom) bool {
+		return uom.DeletedAt == nil && strings.Contains(strings.ToLower(uom.Name), strings.ToLower(name))
+	})
+	if index < 0 {
+		return model.Uom{}, sql.ErrNoRows
+	}
+	return model.Uom{Id: u.store.uoms[index].Id, Name: u.store.uoms[index].Name}, nil
+}
+
+func (u *uomRepository) Update(payload model.Uom) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.uoms, func(uom model.Uom) bool {
+		return uom.Id == payload.Id && uom.Version == payload.Version && uom.DeletedAt == nil
+	})
+	if index < 0 {
+		return exceptions.ErrVersionConflict
+	}
+	u.store.uoms[index].Name = payload.Name
+	u.store.uoms[index].Version++
+	return nil
+}
+
+func (u *uomRepository) Delete(id string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.uoms, func(uom model.Uom) bool { return uom.Id == id && uom.DeletedAt == nil })
+	if index >= 0 {
+		u.store.uoms[index].DeletedAt = now()
+	}
+	return nil
+}
+
+func (u *uomRepository) Restore(id string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.uoms, func(uom model.Uom) bool { return uom.Id == id })
+	if index >= 0 {
+		u.store.uoms[index].DeletedAt = nil
+	}
+	return nil
+}
+
+func NewUomRepository(store *Store) repository.UomRepository {
+	return &uomRepository{store: store}
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+// Create implements UserRepository.
+func (u *userRepository) Create(payload model.UserCredential) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	if indexOf(u.store.users, func(user model.UserCredential) bool { return user.Id == payload.Id }) >= 0 {
+		return uniqueViolation("user_credential_pkey")
+	}
+	if indexOf(u.store.users, func(user model.UserCredential) bool { return user.Username == payload.Username }) >= 0 {
+		return uniqueViolation("user_credential_username_key")
+	}
+	if err := u.store.checkUserEmployee(payload.Id, payload.EmployeeId); err != nil {
+		return err
+	}
+	// is_active tidak diisi saat insert, jadi memakai default true
+	u.store.users = append(u.store.users, model.UserCredential{
+		Id:         payload.Id,
+		Username:   payload.Username,
+		Password:   payload.Password,
+		Role:       payload.Role,
+		IsActive:   true,
+		EmployeeId: payload.EmployeeId,
+	})
+	return nil
+}
+
+// Get implements UserRepository.
+func (u *userRepository) Get(id string) (model.UserCredential, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	index := indexOf(u.store.users, func(user model.UserCredential) bool { return user.Id == id })
+	if index < 0 {
+		return model.UserCredential{}, sql.ErrNoRows
+	}
+	user := u.store.users[index]
+	user.Password = ""
+	return user, nil
+}
+
+// GetUsername implements UserRepository.
+func (u *userRepository) GetUsername(username string) (model.UserCredential, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	index := indexOf(u.store.users, func(user model.UserCredential) bool { return user.IsActive && user.Username == username })
+	if index < 0 {
+		return model.UserCredential{}, sql.ErrNoRows
+	}
+	return u.store.users[index], nil
+}
+
+// GetUsernamePassword implements UserRepository.
+func (u *userRepository) GetUsernamePassword(username string, password string) (model.UserCredential, error) {
+	user, err := u.GetUsername(username)
+	if err != nil {
+		return model.UserCredential{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		return model.UserCredential{}, fmt.Errorf("failed to verify password hash : %v", err)
+	}
+	return user, nil
+}
+
+// List implements UserRepository.
+func (u *userRepository) List() ([]model.UserCredential, error) {
+	u.store.mu.RLock()
+	defer u.store.mu.RUnlock()
+
+	var users []model.UserCredential
+	for _, user := range u.store.users {
+		user.Password = ""
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// UpdatePassword implements UserRepository.
+func (u *userRepository) UpdatePassword(id string, password string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.users, func(user model.UserCredential) bool { return user.Id == id })
+	if index >= 0 {
+		u.store.users[index].Password = password
+	}
+	return nil
+}
+
+// UpdateActive implements UserRepository.
+func (u *userRepository) UpdateActive(id string, isActive bool) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.users, func(user model.UserCredential) bool { return user.Id == id })
+	if index >= 0 {
+		u.store.users[index].IsActive = isActive
+	}
+	return nil
+}
+
+// UpdateEmployee implements UserRepository.
+func (u *userRepository) UpdateEmployee(id string, employeeId string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	index := indexOf(u.store.users, func(user model.UserCredential) bool { return user.Id == id })
+	if index < 0 {
+		return nil
+	}
+	if err := u.store.checkUserEmployee(id, employeeId); err != nil {
+		return err
+	}
+	u.store.users[index].EmployeeId = employeeId
+	return nil
+}
+
+// Delete implements UserRepository.
+func (u *userRepository) Delete(id string) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	// session dan refresh token ikut dihapus seperti pada repository postgres
+	sessionIds := make(map[string]bool)
+	for _, session := range u.store.sessions {
+		if session.UserId == id {
+			sessionIds[session.Id] = true
+		}
+	}
+	u.store.refreshTokens = filter(u.store.refreshTokens, func(token model.RefreshToken) bool { return !sessionIds[token.SessionId] })
+	u.store.sessions = filter(u.store.sessions, func(session model.Session) bool { return session.UserId != id })
+	u.store.users = filter(u.store.users, func(user model.UserCredential) bool { return user.Id != id })
+	return nil
+}
+
+// employee_id boleh kosong, kalau diisi harus ada di tabel employee dan belum dipakai user lain
+func (s *Store) checkUserEmployee(userId string, employeeId string) error {
+	if employeeId == "" {
+		return nil
+	}
+	if indexOf(s.users, func(user model.UserCredential) bool { return user.Id != userId && user.EmployeeId == employeeId }) >= 0 {
+		return uniqueViolation("user_credential_employee_id_key")
+	}
+	if !s.hasEmployee(employeeId) {
+		return foreignKeyViolation("user_credential", "user_credential_employee_id_fkey")
+	}
+	return nil
+}
+
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{store: store}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type PaymentRepository interface {
+	Create(payload model.Payment) error
+	ListByBillId(billId string) ([]model.Payment, error)
+}
+
+type paymentRepository struct {
+	db *sql.DB
+}
+
+// Create implements PaymentRepository.
+func (p *paymentRepository) Create(payload model.Payment) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// baris bill dikunci dulu supaya dua pembayaran yang bersamaan tidak sama-sama lolos cek sisa tagihan,
+	// sqlite tidak punya SELECT ... FOR UPDATE jadi dipakai UPDATE yang tidak mengubah apa-apa
+	result, err := tx.Exec("UPDATE bill SET status = status WHERE id = $1", payload.BillId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("bill with ID %s not found", payload.BillId)
+	}
+
+	var status string
+	var balanceDue int
+	err = tx.QueryRow(`SELECT b.status,
+	(SELECT COALESCE(SUM(bd.product_price * bd.qty), 0) FROM bill_detail bd WHERE bd.bill_id = b.id) -
+	(SELECT COALESCE(SUM(py.amount), 0) FROM payment py WHERE py.bill_id = b.id)
+	FROM bill b WHERE b.id = $1`, payload.BillId).Scan(&status, &balanceDue)
+	if err != nil {
+		return err
+	}
+	if status == model.BillStatusCancelled {
+		return fmt.Errorf("bill with ID %s is cancelled", payload.BillId)
+	}
+	if payload.Amount > balanceDue {
+		return fmt.Errorf("%w: amount %d, balance due %d", exceptions.ErrPaymentExceedsBalance, payload.Amount, balanceDue)
+	}
+
+	_, err = tx.Exec("INSERT INTO payment (id, bill_id, amount, method, payment_date, received_by) VALUES ($1, $2, $3, $4, $5, $6)", payload.Id, payload.BillId, payload.Amount, payload.Method, payload.PaymentDate, payload.ReceivedBy)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListByBillId implements PaymentRepository.
+func (p *paymentRepository) ListByBillId(billId string) ([]model.Payment, error) {
+	rows, err := p.db.Query("SELECT id, bill_id, amount, method, payment_date, received_by, COALESCE(reversal_of, '') FROM payment WHERE bill_id = $1 ORDER BY payment_date", billId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.Payment
+	for rows.Next() {
+		var payment model.Payment
+		err := rows.Scan(&payment.Id, &payment.BillId, &payment.Amount, &payment.Method, &payment.PaymentDate, &payment.ReceivedBy, &payment.ReversalOf)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
+func NewPaymentRepository(db *sql.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
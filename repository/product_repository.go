@@ -18,7 +18,7 @@ type productRepository struct {
 }
 
 func (p *productRepository) Create(payload model.Product) error {
-	_, err := p.db.Exec("INSERT INTO product (id, name, price, uom_id) VALUES ($1, $2, $3, $4)", payload.Id, payload.Name, payload.Price, payload.Uom.Id)
+	_, err := p.db.Exec("INSERT INTO product (id, name, price, uom_id, turnaround_hours) VALUES ($1, $2, $3, $4, $5)", payload.Id, payload.Name, payload.Price, payload.Uom.Id, payload.TurnaroundHours)
 	if err != nil {
 		return err
 	}
@@ -26,7 +26,7 @@ func (p *productRepository) Create(payload model.Product) error {
 }
 
 func (p *productRepository) List() ([]model.Product, error) {
-	rows, err := p.db.Query("SELECT p.id, p.name, p.price, u.id, u.name FROM product p INNER JOIN uom u ON u.id = p.uom_id")
+	rows, err := p.db.Query("SELECT p.id, p.name, p.price, p.turnaround_hours, u.id, u.name, p.version FROM product p INNER JOIN uom u ON u.id = p.uom_id WHERE p.deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,7 @@ func (p *productRepository) List() ([]model.Product, error) {
 	var products []model.Product
 	for rows.Next() {
 		var product model.Product
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Uom.Id, &product.Uom.Name)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.TurnaroundHours, &product.Uom.Id, &product.Uom.Name, &product.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -44,25 +44,44 @@ func (p *productRepository) List() ([]model.Product, error) {
 }
 
 func (p *productRepository) Get(id string) (model.Product, error) {
+	return p.get("SELECT p.id, p.name, p.price, p.turnaround_hours, u.id, u.name, p.version, p.deleted_at FROM product p INNER JOIN uom u ON u.id = p.uom_id WHERE p.id = $1 AND p.deleted_at IS NULL", id)
+}
+
+func (p *productRepository) GetIncludeDeleted(id string) (model.Product, error) {
+	return p.get("SELECT p.id, p.name, p.price, p.turnaround_hours, u.id, u.name, p.version, p.deleted_at FROM product p INNER JOIN uom u ON u.id = p.uom_id WHERE p.id = $1", id)
+}
+
+func (p *productRepository) get(query string, id string) (model.Product, error) {
 	var product model.Product
-	row := p.db.QueryRow("SELECT p.id, p.name, p.price, u.id, u.name FROM product p INNER JOIN uom u ON u.id = p.uom_id WHERE p.id = $1", id)
-	err := row.Scan(&product.Id, &product.Name, &product.Price, &product.Uom.Id, &product.Uom.Name)
+	var deletedAt sql.NullTime
+	err := p.db.QueryRow(query, id).Scan(&product.Id, &product.Name, &product.Price, &product.TurnaroundHours, &product.Uom.Id, &product.Uom.Name, &product.Version, &deletedAt)
 	if err != nil {
 		return model.Product{}, err
 	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.Time
+	}
 	return product, nil
 }
 
 func (p *productRepository) Update(payload model.Product) error {
-	_, err := p.db.Exec("UPDATE product SET name = $2, price = $3, uom_id = $4 WHERE id = $1", payload.Id, payload.Name, payload.Price, payload.Uom.Id)
+	result, err := p.db.Exec("UPDATE product SET name = $2, price = $3, uom_id = $4, turnaround_hours = $5, version = version + 1 WHERE id = $1 AND version = $6 AND deleted_at IS NULL", payload.Id, payload.Name, payload.Price, payload.Uom.Id, payload.TurnaroundHours, payload.Version)
 	if err != nil {
 		return err
 	}
-	return nil
+	return checkVersion(result)
 }
 
 func (p *productRepository) Delete(id string) error {
-	_, err := p.db.Exec("DELETE FROM product WHERE id = $1", id)
+	_, err := p.db.Exec("UPDATE product SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *productRepository) Restore(id string) error {
+	_, err := p.db.Exec("UPDATE product SET deleted_at = NULL WHERE id = $1", id)
 	if err != nil {
 		return err
 	}
@@ -73,7 +92,8 @@ func (p *productRepository) Paging(requestPaging dto.PaginationParam) ([]model.P
 	var paginationQuery dto.PaginationQuery
 	paginationQuery = common.GetPaginationParams(requestPaging)
 
-	rows, err := p.db.Query("SELECT p.id, p.name, p.price, u.id, u.name FROM product p INNER JOIN uom u ON u.id = p.uom_id LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
+	where := notDeleted("p.deleted_at", requestPaging.IncludeDeleted)
+	rows, err := p.db.Query("SELECT p.id, p.name, p.price, p.turnaround_hours, u.id, u.name, p.version, p.deleted_at FROM product p INNER JOIN uom u ON u.id = p.uom_id "+where+" LIMIT $1 OFFSET $2", paginationQuery.Take, paginationQuery.Skip)
 	if err != nil {
 		return nil, dto.Paging{}, err
 	}
@@ -81,15 +101,19 @@ func (p *productRepository) Paging(requestPaging dto.PaginationParam) ([]model.P
 	var products []model.Product
 	for rows.Next() {
 		var product model.Product
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Uom.Id, &product.Uom.Name)
+		var deletedAt sql.NullTime
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.TurnaroundHours, &product.Uom.Id, &product.Uom.Name, &product.Version, &deletedAt)
 		if err != nil {
 			return nil, dto.Paging{}, err
 		}
+		if deletedAt.Valid {
+			product.DeletedAt = &deletedAt.Time
+		}
 		products = append(products, product)
 	}
 	
 	var totalRows int
-	row := p.db.QueryRow("SELECT COUNT(*) FROM product")
+	row := p.db.QueryRow("SELECT COUNT(*) FROM product p " + where)
 	err = row.Scan(&totalRows)
 	if err != nil {
 		return nil, dto.Paging{}, err
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+)
+
+type ReportRepository interface {
+	ListDailyRevenue(from time.Time, to time.Time) ([]dto.RevenueRowDto, error)
+	ListDailyOrderCount(from time.Time, to time.Time) ([]dto.OrderCountRowDto, error)
+	ListEmployeeBills(from time.Time, to time.Time) ([]dto.EmployeeBillRowDto, error)
+}
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+// ListDailyRevenue implements ReportRepository.
+func (r *reportRepository) ListDailyRevenue(from time.Time, to time.Time) ([]dto.RevenueRowDto, error) {
+	sqlRevenue := `SELECT b.bill_date, p.id, p.name, u.id, u.name, SUM(bd.qty), SUM(bd.product_price * bd.qty)
+	FROM bill b
+	JOIN bill_detail bd ON bd.bill_id = b.id
+	JOIN product p ON p.id = bd.product_id
+	JOIN uom u ON u.id = p.uom_id
+	WHERE b.bill_date >= $1 AND b.bill_date <= $2 AND b.status <> $3
+	GROUP BY b.bill_date, p.id, p.name, u.id, u.name
+	ORDER BY b.bill_date, p.name`
+	rows, err := r.db.Query(sqlRevenue, from.Format("2006-01-02"), to.Format("2006-01-02"), model.BillStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revenues []dto.RevenueRowDto
+	for rows.Next() {
+		var revenue dto.RevenueRowDto
+		err := rows.Scan(&revenue.BillDate, &revenue.ProductId, &revenue.ProductName, &revenue.UomId, &revenue.UomName, &revenue.Qty, &revenue.Revenue)
+		if err != nil {
+			return nil, err
+		}
+		revenues = append(revenues, revenue)
+	}
+	return revenues, nil
+}
+
+// ListDailyOrderCount implements ReportRepository.
+func (r *reportRepository) ListDailyOrderCount(from time.Time, to time.Time) ([]dto.OrderCountRowDto, error) {
+	rows, err := r.db.Query("SELECT bill_date, COUNT(id) FROM bill WHERE bill_date >= $1 AND bill_date <= $2 AND status <> $3 GROUP BY bill_date ORDER BY bill_date", from.Format("2006-01-02"), to.Format("2006-01-02"), model.BillStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orderCounts []dto.OrderCountRowDto
+	for rows.Next() {
+		var orderCount dto.OrderCountRowDto
+		if err := rows.Scan(&orderCount.BillDate, &orderCount.Orders); err != nil {
+			return nil, err
+		}
+		orderCounts = append(orderCounts, orderCount)
+	}
+	return orderCounts, nil
+}
+
+// ListEmployeeBills implements ReportRepository.
+func (r *reportRepository) ListEmployeeBills(from time.Time, to time.Time) ([]dto.EmployeeBillRowDto, error) {
+	// ready_at diambil dari riwayat status, yaitu kapan bill pertama kali selesai dikerjakan
+	sqlEmployeeBill := `SELECT b.employee_id, b.entry_date, b.finish_date,
+	(SELECT COALESCE(SUM(bd.product_price * bd.qty), 0) FROM bill_detail bd WHERE bd.bill_id = b.id) as total,
+	(SELECT MIN(h.changed_at) FROM bill_status_history h WHERE h.bill_id = b.id AND h.to_status = $3) as ready_at
+	FROM bill b
+	WHERE b.bill_date >= $1 AND b.bill_date <= $2 AND b.status <> $4`
+	rows, err := r.db.Query(sqlEmployeeBill, from.Format("2006-01-02"), to.Format("2006-01-02"), model.BillStatusReady, model.BillStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employeeBills []dto.EmployeeBillRowDto
+	for rows.Next() {
+		var employeeBill dto.EmployeeBillRowDto
+		var readyAt nullTime
+		err := rows.Scan(&employeeBill.EmployeeId, &employeeBill.EntryDate, &employeeBill.FinishDate, &employeeBill.Total, &readyAt)
+		if err != nil {
+			return nil, err
+		}
+		if readyAt.Valid {
+			employeeBill.ReadyAt = &readyAt.Time
+		}
+		employeeBills = append(employeeBills, employeeBill)
+	}
+	return employeeBills, nil
+}
+
+func NewReportRepository(db *sql.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
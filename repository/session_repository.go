@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+type SessionRepository interface {
+	Create(session model.Session, refreshToken model.RefreshToken) error
+	Get(id string) (model.Session, error)
+	GetRefreshToken(tokenHash string) (model.RefreshToken, error)
+	Rotate(usedTokenId string, usedAt time.Time, newToken model.RefreshToken) error
+	Revoke(id string, revokedAt time.Time) error
+	RevokeByUser(userId string, revokedAt time.Time) error
+}
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+// Create implements SessionRepository.
+func (s *sessionRepository) Create(session model.Session, refreshToken model.RefreshToken) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO user_session (id, user_id, created_at) VALUES ($1, $2, $3)", session.Id, session.UserId, session.CreatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO refresh_token (id, session_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)", refreshToken.Id, refreshToken.SessionId, refreshToken.TokenHash, refreshToken.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Get implements SessionRepository.
+func (s *sessionRepository) Get(id string) (model.Session, error) {
+	var session model.Session
+	var revokedAt sql.NullTime
+	err := s.db.QueryRow("SELECT id, user_id, created_at, revoked_at FROM user_session WHERE id = $1", id).Scan(&session.Id, &session.UserId, &session.CreatedAt, &revokedAt)
+	if err != nil {
+		return model.Session{}, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return session, nil
+}
+
+// GetRefreshToken implements SessionRepository.
+func (s *sessionRepository) GetRefreshToken(tokenHash string) (model.RefreshToken, error) {
+	var refreshToken model.RefreshToken
+	var usedAt sql.NullTime
+	err := s.db.QueryRow("SELECT id, session_id, token_hash, expires_at, used_at FROM refresh_token WHERE token_hash = $1", tokenHash).Scan(&refreshToken.Id, &refreshToken.SessionId, &refreshToken.TokenHash, &refreshToken.ExpiresAt, &usedAt)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+	if usedAt.Valid {
+		refreshToken.UsedAt = &usedAt.Time
+	}
+	return refreshToken, nil
+}
+
+// Rotate implements SessionRepository.
+func (s *sessionRepository) Rotate(usedTokenId string, usedAt time.Time, newToken model.RefreshToken) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// token lama hanya boleh dipakai sekali, kalau sudah terpakai berarti ada yang memakai ulang
+	result, err := tx.Exec("UPDATE refresh_token SET used_at = $2 WHERE id = $1 AND used_at IS NULL", usedTokenId, usedAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("refresh token has already been used")
+	}
+
+	_, err = tx.Exec("INSERT INTO refresh_token (id, session_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)", newToken.Id, newToken.SessionId, newToken.TokenHash, newToken.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Revoke implements SessionRepository.
+func (s *sessionRepository) Revoke(id string, revokedAt time.Time) error {
+	_, err := s.db.Exec("UPDATE user_session SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL", id, revokedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeByUser implements SessionRepository.
+func (s *sessionRepository) RevokeByUser(userId string, revokedAt time.Time) error {
+	_, err := s.db.Exec("UPDATE user_session SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL", userId, revokedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
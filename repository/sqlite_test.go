@@ -0,0 +1,23 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+// kolom timestamp di sqlite, sama seperti postgres, hanya menyimpan jam tanpa zona waktu dan dibaca kembali sebagai UTC
+func TestSqliteTimestampWithoutZone(t *testing.T) {
+	repo := newSqliteRepo(t)
+	seedMasterData(t, repo)
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, shopLocation)
+
+	mustNoErr(t, repo.UserRepo().Create(model.UserCredential{Id: "us1", Username: "admin", Password: "hash", Role: model.RoleAdmin}))
+	mustNoErr(t, repo.SessionRepo().Create(model.Session{Id: "s1", UserId: "us1", CreatedAt: now}, model.RefreshToken{Id: "r1", SessionId: "s1", TokenHash: "hash1", ExpiresAt: now.Add(time.Hour)}))
+	refreshToken, err := repo.SessionRepo().GetRefreshToken("hash1")
+	mustNoErr(t, err)
+	if !refreshToken.ExpiresAt.Equal(time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected expires at %v", refreshToken.ExpiresAt)
+	}
+}
@@ -11,6 +11,7 @@ import (
 type UomRepository interface {
 	BaseRepository[model.Uom]
 	GetByName(name string) (model.Uom, error)
+	ListIncludeDeleted() ([]model.Uom, error)
 }
 
 type uomRepository struct {
@@ -27,7 +28,15 @@ func (u *uomRepository) Create(payload model.Uom) error {
 }
 
 func (u *uomRepository) List() ([]model.Uom, error) {
-	rows, err := u.db.Query("SELECT id, name FROM uom")
+	return u.list(false)
+}
+
+func (u *uomRepository) ListIncludeDeleted() ([]model.Uom, error) {
+	return u.list(true)
+}
+
+func (u *uomRepository) list(includeDeleted bool) ([]model.Uom, error) {
+	rows, err := u.db.Query("SELECT id, name, version, deleted_at FROM uom " + notDeleted("deleted_at", includeDeleted))
 	if err != nil {
 		return nil, err
 	}
@@ -35,21 +44,37 @@ func (u *uomRepository) List() ([]model.Uom, error) {
 	var uoms []model.Uom
 	for rows.Next() {
 		var uom model.Uom
-		err := rows.Scan(&uom.Id, &uom.Name)
+		var deletedAt sql.NullTime
+		err := rows.Scan(&uom.Id, &uom.Name, &uom.Version, &deletedAt)
 		if err != nil {
 			return nil, err
 		}
+		if deletedAt.Valid {
+			uom.DeletedAt = &deletedAt.Time
+		}
 		uoms = append(uoms, uom)
 	}
 	return uoms, nil
 }
 
 func (u *uomRepository) Get(id string) (model.Uom, error) {
+	return u.get("SELECT id, name, version, deleted_at FROM uom WHERE id=$1 AND deleted_at IS NULL", id)
+}
+
+func (u *uomRepository) GetIncludeDeleted(id string) (model.Uom, error) {
+	return u.get("SELECT id, name, version, deleted_at FROM uom WHERE id=$1", id)
+}
+
+func (u *uomRepository) get(query string, id string) (model.Uom, error) {
 	var uom model.Uom
-	err := u.db.QueryRow("SELECT id, name FROM uom WHERE id=$1", id).Scan(&uom.Id, &uom.Name)
+	var deletedAt sql.NullTime
+	err := u.db.QueryRow(query, id).Scan(&uom.Id, &uom.Name, &uom.Version, &deletedAt)
 	if err != nil {
 		return model.Uom{}, err
 	}
+	if deletedAt.Valid {
+		uom.DeletedAt = &deletedAt.Time
+	}
 	return uom, nil
 }
 
@@ -57,7 +82,8 @@ func (u *uomRepository) GetByName(name string) (model.Uom, error) {
 	var uom model.Uom
 	// LIKE => case sensitive e.g L l (ngaruh)
 	// ILIKE => in case sensitibe e.g L l (tidak ngaruh) (hanya ada di postgre)
-	err := u.db.QueryRow("SELECT id, name FROM uom WHERE name ILIKE $1", "%"+name+"%").Scan(&uom.Id, &uom.Name)
+	// LOWER di kedua sisi supaya hasilnya sama dengan ILIKE di postgres maupun sqlite
+	err := u.db.QueryRow("SELECT id, name FROM uom WHERE LOWER(name) LIKE LOWER($1) AND deleted_at IS NULL", "%"+name+"%").Scan(&uom.Id, &uom.Name)
 	if err != nil {
 		return model.Uom{}, err
 	}
@@ -65,15 +91,23 @@ func (u *uomRepository) GetByName(name string) (model.Uom, error) {
 }
 
 func (u *uomRepository) Update(payload model.Uom) error {
-	_, err := u.db.Exec("UPDATE uom SET name=$1 WHERE id=$2", payload.Name, payload.Id)
+	result, err := u.db.Exec("UPDATE uom SET name=$1, version = version + 1 WHERE id=$2 AND version=$3 AND deleted_at IS NULL", payload.Name, payload.Id, payload.Version)
 	if err != nil {
 		return err
 	}
-	return nil
+	return checkVersion(result)
 }
 
 func (u *uomRepository) Delete(id string) error {
-	_, err := u.db.Exec("DELETE FROM uom WHERE id=$1", id)
+	_, err := u.db.Exec("UPDATE uom SET deleted_at = CURRENT_TIMESTAMP WHERE id=$1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *uomRepository) Restore(id string) error {
+	_, err := u.db.Exec("UPDATE uom SET deleted_at = NULL WHERE id=$1", id)
 	if err != nil {
 		return err
 	}
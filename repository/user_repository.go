@@ -11,6 +11,11 @@ import (
 type UserRepository interface {
 	Create(payload model.UserCredential) error
 	List() ([]model.UserCredential, error)
+	Get(id string) (model.UserCredential, error)
+	UpdatePassword(id string, password string) error
+	UpdateActive(id string, isActive bool) error
+	UpdateEmployee(id string, employeeId string) error
+	Delete(id string) error
 	GetUsername(username string) (model.UserCredential, error)
 	GetUsernamePassword(username string, password string) (model.UserCredential, error)
 }
@@ -21,20 +26,34 @@ type userRepository struct {
 
 // Create implements UserRepository.
 func (u *userRepository) Create(payload model.UserCredential) error {
-	_, err := u.db.Exec("INSERT INTO user_credential(id, username, password) VALUES ($1, $2, $3)", payload.Id, payload.Username, payload.Password)
+	_, err := u.db.Exec("INSERT INTO user_credential(id, username, password, role, employee_id) VALUES ($1, $2, $3, $4, $5)", payload.Id, payload.Username, payload.Password, payload.Role, nullString(payload.EmployeeId))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Get implements UserRepository.
+func (u *userRepository) Get(id string) (model.UserCredential, error) {
+	var user model.UserCredential
+	var employeeId sql.NullString
+	err := u.db.QueryRow("SELECT id, username, role, is_active, employee_id FROM user_credential WHERE id = $1", id).Scan(&user.Id, &user.Username, &user.Role, &user.IsActive, &employeeId)
+	if err != nil {
+		return model.UserCredential{}, err
+	}
+	user.EmployeeId = employeeId.String
+	return user, nil
+}
+
 // GetUsername implements UserRepository.
 func (u *userRepository) GetUsername(username string) (model.UserCredential, error) {
 	var user model.UserCredential
-	err := u.db.QueryRow("SELECT id, username, password FROM user_credential WHERE is_active = $1 AND username = $2", true, username).Scan(&user.Id, &user.Username, &user.Password)
+	var employeeId sql.NullString
+	err := u.db.QueryRow("SELECT id, username, password, role, is_active, employee_id FROM user_credential WHERE is_active = $1 AND username = $2", true, username).Scan(&user.Id, &user.Username, &user.Password, &user.Role, &user.IsActive, &employeeId)
 	if err != nil {
 		return model.UserCredential{}, err
 	}
+	user.EmployeeId = employeeId.String
 	return user, nil
 }
 
@@ -56,21 +75,79 @@ func (u *userRepository) GetUsernamePassword(username string, password string) (
 
 func (u *userRepository) List() ([]model.UserCredential, error) {
 	var users []model.UserCredential
-	rows, err := u.db.Query("SELECT id, username, is_active FROM user_credential")
+	rows, err := u.db.Query("SELECT id, username, role, is_active, employee_id FROM user_credential")
 	if err != nil {
 		return nil, err
 	}
 	for rows.Next() {
 		var user model.UserCredential
-		err := rows.Scan(&user.Id, &user.Username, &user.IsActive)
+		var employeeId sql.NullString
+		err := rows.Scan(&user.Id, &user.Username, &user.Role, &user.IsActive, &employeeId)
 		if err != nil {
 			return nil, err
 		}
+		user.EmployeeId = employeeId.String
 		users = append(users, user)
 	}
 	return users, nil
 }
 
+// UpdatePassword implements UserRepository.
+func (u *userRepository) UpdatePassword(id string, password string) error {
+	_, err := u.db.Exec("UPDATE user_credential SET password = $2 WHERE id = $1", id, password)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateActive implements UserRepository.
+func (u *userRepository) UpdateActive(id string, isActive bool) error {
+	_, err := u.db.Exec("UPDATE user_credential SET is_active = $2 WHERE id = $1", id, isActive)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateEmployee implements UserRepository.
+func (u *userRepository) UpdateEmployee(id string, employeeId string) error {
+	_, err := u.db.Exec("UPDATE user_credential SET employee_id = $2 WHERE id = $1", id, nullString(employeeId))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete implements UserRepository.
+func (u *userRepository) Delete(id string) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// session dan refresh token ikut dihapus karena foreign key ke user_credential
+	_, err = tx.Exec("DELETE FROM refresh_token WHERE session_id IN (SELECT id FROM user_session WHERE user_id = $1)", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM user_session WHERE user_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM user_credential WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// employee_id boleh kosong, simpan sebagai NULL supaya tidak bentrok dengan unique constraint
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
 func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{db: db}
 }
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+)
+
+type AuditUseCase interface {
+	Record(actor string, action string, entityType string, entityId string, before any, after any)
+	FindAllAudit(filter dto.AuditFilterDto) ([]model.AuditLog, dto.Paging, error)
+}
+
+type auditUseCase struct {
+	repo repository.AuditRepository
+	cfg  config.BusinessConfig
+}
+
+// Record implements AuditUseCase.
+// perubahan data sudah tersimpan saat Record dipanggil, jadi gagal menulis audit cukup di log
+// supaya client tidak mengulang request yang sebenarnya sudah berhasil
+func (a *auditUseCase) Record(actor string, action string, entityType string, entityId string, before any, after any) {
+	audit := model.AuditLog{
+		Id:         common.GenerateID(),
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		CreatedAt:  time.Now().In(a.cfg.Location),
+	}
+	var err error
+	if audit.Before, err = marshalAudit(before); err != nil {
+		log.Printf("failed to write audit log %s %s %s: %v", action, entityType, entityId, err)
+		return
+	}
+	if audit.After, err = marshalAudit(after); err != nil {
+		log.Printf("failed to write audit log %s %s %s: %v", action, entityType, entityId, err)
+		return
+	}
+	if err := a.repo.Create(audit); err != nil {
+		log.Printf("failed to write audit log %s %s %s: %v", action, entityType, entityId, err)
+	}
+}
+
+// FindAllAudit implements AuditUseCase.
+func (a *auditUseCase) FindAllAudit(filter dto.AuditFilterDto) ([]model.AuditLog, dto.Paging, error) {
+	return a.repo.Paging(filter)
+}
+
+func marshalAudit(data any) (json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+func NewAuditUseCase(repo repository.AuditRepository, cfg config.BusinessConfig) AuditUseCase {
+	return &auditUseCase{repo: repo, cfg: cfg}
+}
@@ -1,36 +1,271 @@
 package usecase
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 	"github.com/NursiNursi/laundry-apps/utils/security"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type AuthUseCase interface {
-	Login(username string, password string) (string, error)
+	Login(username string, password string, clientIp string) (dto.AuthResponseDto, error)
+	RefreshToken(refreshToken string) (dto.AuthResponseDto, error)
+	Logout(sessionId string) error
+	VerifyAccessToken(token string) (jwt.MapClaims, error)
+	FindAllLockout(activeOnly bool) ([]model.LoginLockout, error)
+	UnlockLockout(id string, actor string) error
 }
 
 type authUseCase struct {
-	usecase UserUseCase
-	// JWT
-	// service token jwt
+	usecase     UserUseCase
+	sessionRepo repository.SessionRepository
+	cache       *security.SessionCache
+	cfg         config.TokenConfig
+	loginRepo   repository.LoginAttemptRepository
+	loginCfg    config.LoginConfig
 }
 
 // Login implements AuthUseCase.
-func (a *authUseCase) Login(username string, password string) (string, error) {
+func (a *authUseCase) Login(username string, password string, clientIp string) (dto.AuthResponseDto, error) {
+	now := time.Now().UTC()
+	// cek lockout dulu supaya percobaan yang ditolak tidak sampai menjalankan bcrypt
+	if err := a.checkLoginAttempt(now, username, clientIp); err != nil {
+		return dto.AuthResponseDto{}, err
+	}
+
 	user, err := a.usecase.FindByUsernamePassword(username, password)
 	if err != nil {
-		return "", fmt.Errorf("invalid username or password")
+		if err := a.recordLoginFailure(now, username, clientIp); err != nil {
+			return dto.AuthResponseDto{}, err
+		}
+		return dto.AuthResponseDto{}, fmt.Errorf("invalid username or password")
+	}
+	// hanya hitungan username yang di reset, hitungan ip dibiarkan habis sendiri sesuai window
+	// supaya login ke akun sendiri tidak bisa dipakai untuk menebak password akun lain tanpa batas
+	if err := a.loginRepo.Reset(model.LoginAttemptUsername, username); err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to reset login attempt: %v", err)
+	}
+
+	session := model.Session{
+		Id:        common.GenerateID(),
+		UserId:    user.Id,
+		CreatedAt: time.Now().UTC(),
+	}
+	token, hash, err := security.GenerateRefreshToken()
+	if err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to generate token")
+	}
+	refreshToken := a.newRefreshToken(session.Id, hash)
+	if err := a.sessionRepo.Create(session, refreshToken); err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to create session: %v", err)
+	}
+
+	accessToken, err := security.CreateAccessToken(user, session.Id)
+	if err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to generate token")
 	}
+	return dto.AuthResponseDto{Token: accessToken, RefreshToken: token}, nil
+}
 
-	// mekanisme jika user itu ada dan valid dia akan membalikan sebuah token (token123)
-	token, err := security.CreateAccessToken(user)
+// RefreshToken implements AuthUseCase.
+func (a *authUseCase) RefreshToken(refreshToken string) (dto.AuthResponseDto, error) {
+	stored, err := a.sessionRepo.GetRefreshToken(security.HashRefreshToken(refreshToken))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token")
+		return dto.AuthResponseDto{}, fmt.Errorf("invalid refresh token")
+	}
+	session, err := a.sessionRepo.Get(stored.SessionId)
+	if err != nil || session.RevokedAt != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("invalid refresh token")
+	}
+
+	// refresh token yang sudah dirotasi dipakai lagi, anggap bocor dan matikan seluruh session
+	if stored.UsedAt != nil {
+		a.revoke(session.Id)
+		return dto.AuthResponseDto{}, fmt.Errorf("refresh token reuse detected, session has been revoked")
+	}
+	now := time.Now().UTC()
+	if now.After(stored.ExpiresAt) {
+		return dto.AuthResponseDto{}, fmt.Errorf("refresh token has expired")
+	}
+
+	user, err := a.usecase.FindByIdUser(session.UserId)
+	if err != nil || !user.IsActive {
+		a.revoke(session.Id)
+		return dto.AuthResponseDto{}, fmt.Errorf("invalid refresh token")
+	}
+
+	token, hash, err := security.GenerateRefreshToken()
+	if err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to generate token")
+	}
+	if err := a.sessionRepo.Rotate(stored.Id, now, a.newRefreshToken(session.Id, hash)); err != nil {
+		// kalah balapan dengan request lain yang memakai token yang sama
+		a.revoke(session.Id)
+		return dto.AuthResponseDto{}, fmt.Errorf("refresh token reuse detected, session has been revoked")
+	}
+
+	accessToken, err := security.CreateAccessToken(user, session.Id)
+	if err != nil {
+		return dto.AuthResponseDto{}, fmt.Errorf("failed to generate token")
+	}
+	return dto.AuthResponseDto{Token: accessToken, RefreshToken: token}, nil
+}
+
+// Logout implements AuthUseCase.
+func (a *authUseCase) Logout(sessionId string) error {
+	if err := a.sessionRepo.Revoke(sessionId, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke session: %v", err)
+	}
+	a.cache.Revoke(sessionId)
+	return nil
+}
+
+// VerifyAccessToken implements AuthUseCase.
+func (a *authUseCase) VerifyAccessToken(token string) (jwt.MapClaims, error) {
+	claims, err := security.VerifyAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	sessionId, _ := claims["sid"].(string)
+	if sessionId == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	revoked, ok := a.cache.Get(sessionId)
+	if !ok {
+		session, err := a.sessionRepo.Get(sessionId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token")
+		}
+		revoked = session.RevokedAt != nil
+		a.cache.Set(sessionId, session.UserId, revoked)
+	}
+	if revoked {
+		return nil, fmt.Errorf("session has been revoked")
+	}
+	return claims, nil
+}
+
+// FindAllLockout implements AuthUseCase.
+func (a *authUseCase) FindAllLockout(activeOnly bool) ([]model.LoginLockout, error) {
+	if activeOnly {
+		now := time.Now().UTC()
+		return a.loginRepo.ListLockouts(&now)
+	}
+	return a.loginRepo.ListLockouts(nil)
+}
+
+// UnlockLockout implements AuthUseCase.
+func (a *authUseCase) UnlockLockout(id string, actor string) error {
+	lockout, err := a.loginRepo.GetLockout(id)
+	if err != nil {
+		return fmt.Errorf("lockout with ID %s not found", id)
+	}
+	if lockout.UnlockedAt != nil {
+		return fmt.Errorf("lockout with ID %s has already been unlocked", id)
+	}
+	if err := a.loginRepo.Unlock(lockout.Id, actor, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to unlock: %v", err)
+	}
+	return nil
+}
+
+func (a *authUseCase) checkLoginAttempt(now time.Time, username string, clientIp string) error {
+	var retryAfter time.Duration
+	for kind, value := range map[string]string{model.LoginAttemptUsername: username, model.LoginAttemptIp: clientIp} {
+		attempt, err := a.loginRepo.Get(kind, value)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check login attempt: %v", err)
+		}
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) && attempt.LockedUntil.Sub(now) > retryAfter {
+			retryAfter = attempt.LockedUntil.Sub(now)
+		}
+	}
+	if retryAfter > 0 {
+		return &exceptions.TooManyRequestsError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure menambah hitungan gagal, username kena jeda eksponensial sebelum lockout,
+// ip hanya kena lockout karena satu ip bisa dipakai banyak kasir sekaligus
+func (a *authUseCase) recordLoginFailure(now time.Time, username string, clientIp string) error {
+	windowStart := now.Add(-a.loginCfg.LoginLockoutDuration)
+	var lockedFor time.Duration
+	for kind, value := range map[string]string{model.LoginAttemptUsername: username, model.LoginAttemptIp: clientIp} {
+		failedCount, err := a.loginRepo.RecordFailure(kind, value, now, windowStart)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %v", err)
+		}
+
+		maxAttempts := a.loginCfg.LoginMaxAttempts
+		if kind == model.LoginAttemptIp {
+			maxAttempts = a.loginCfg.LoginMaxIpAttempts
+		}
+
+		var delay time.Duration
+		switch {
+		case failedCount >= maxAttempts:
+			delay = a.loginCfg.LoginLockoutDuration
+			lockout := model.LoginLockout{
+				Id:          common.GenerateID(),
+				Kind:        kind,
+				Value:       value,
+				FailedCount: failedCount,
+				LockedAt:    now,
+				LockedUntil: now.Add(delay),
+			}
+			if err := a.loginRepo.CreateLockout(lockout); err != nil {
+				return fmt.Errorf("failed to record lockout: %v", err)
+			}
+			if delay > lockedFor {
+				lockedFor = delay
+			}
+		case kind == model.LoginAttemptUsername && failedCount >= a.loginCfg.LoginBackoffAfter:
+			delay = a.loginCfg.LoginBackoffBase << (failedCount - a.loginCfg.LoginBackoffAfter)
+			if delay <= 0 || delay > a.loginCfg.LoginLockoutDuration {
+				delay = a.loginCfg.LoginLockoutDuration
+			}
+		default:
+			continue
+		}
+		if err := a.loginRepo.Lock(kind, value, now.Add(delay)); err != nil {
+			return fmt.Errorf("failed to record login attempt: %v", err)
+		}
+	}
+	if lockedFor > 0 {
+		return &exceptions.TooManyRequestsError{RetryAfter: lockedFor}
+	}
+	return nil
+}
+
+func (a *authUseCase) newRefreshToken(sessionId string, hash string) model.RefreshToken {
+	return model.RefreshToken{
+		Id:        common.GenerateID(),
+		SessionId: sessionId,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(a.cfg.RefreshTokenLifeTime),
+	}
+}
+
+func (a *authUseCase) revoke(sessionId string) {
+	if err := a.sessionRepo.Revoke(sessionId, time.Now().UTC()); err == nil {
+		a.cache.Revoke(sessionId)
 	}
-	return token, nil
 }
 
-func NewAuthUseCase(usecase UserUseCase) AuthUseCase {
-	return &authUseCase{usecase: usecase}
+func NewAuthUseCase(usecase UserUseCase, sessionRepo repository.SessionRepository, cache *security.SessionCache, cfg config.TokenConfig, loginRepo repository.LoginAttemptRepository, loginCfg config.LoginConfig) AuthUseCase {
+	return &authUseCase{usecase: usecase, sessionRepo: sessionRepo, cache: cache, cfg: cfg, loginRepo: loginRepo, loginCfg: loginCfg}
 }
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+// login yang berhasil ke akun sendiri tidak boleh menghapus hitungan gagal per ip,
+// kalau tidak penyerang bisa menebak password akun lain tanpa pernah kena lockout ip
+func TestLoginSuccessKeepsIpCounter(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.LoginBackoffAfter = 100
+	cfg.LoginMaxAttempts = 100
+	cfg.LoginMaxIpAttempts = 3
+	ucm := newUseCaseManager(t, cfg)
+	mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-penyerang", Username: "penyerang", Password: "Rahasia123"}, "system", model.RoleOwner))
+	for _, username := range []string{"ani", "budi", "citra"} {
+		mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-" + username, Username: username, Password: "Rahasia123"}, "system", model.RoleOwner))
+	}
+
+	authUC := ucm.AuthUseCase()
+	const clientIp = "10.0.0.7"
+	var tooMany *exceptions.TooManyRequestsError
+	for i, victim := range []string{"ani", "budi"} {
+		if _, err := authUC.Login(victim, "tebakan", clientIp); err == nil || errors.As(err, &tooMany) {
+			t.Fatalf("guess %d should fail without lockout, got %v", i+1, err)
+		}
+		if _, err := authUC.Login("penyerang", "Rahasia123", clientIp); err != nil {
+			t.Fatalf("login to own account should succeed, got %v", err)
+		}
+	}
+	// tebakan ketiga dari ip yang sama mencapai LoginMaxIpAttempts walaupun diselingi login yang berhasil
+	if _, err := authUC.Login("citra", "tebakan", clientIp); !errors.As(err, &tooMany) {
+		t.Fatalf("ip should be locked after %d failures, got %v", cfg.LoginMaxIpAttempts, err)
+	}
+	if _, err := authUC.Login("citra", "Rahasia123", clientIp); !errors.As(err, &tooMany) {
+		t.Errorf("locked ip should be rejected even with the right password, got %v", err)
+	}
+	// username yang benar tetap bisa login dari ip lain, hitungan username di reset setelah berhasil
+	if _, err := authUC.Login("penyerang", "Rahasia123", "10.0.0.8"); err != nil {
+		t.Errorf("login from another ip should succeed, got %v", err)
+	}
+}
+
+// refresh token lama yang dipakai ulang setelah rotasi dianggap bocor, seluruh session ikut dimatikan
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	ucm := newUseCaseManager(t, newTestConfig(t))
+	mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-ani", Username: "ani", Password: "Rahasia123"}, "system", model.RoleOwner))
+	authUC := ucm.AuthUseCase()
+
+	first, err := authUC.Login("ani", "Rahasia123", "10.0.0.7")
+	mustNoErr(t, err)
+	second, err := authUC.RefreshToken(first.RefreshToken)
+	mustNoErr(t, err)
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token should be rotated")
+	}
+	if _, err := authUC.VerifyAccessToken(second.Token); err != nil {
+		t.Fatalf("rotated access token should be valid, got %v", err)
+	}
+
+	if _, err := authUC.RefreshToken(first.RefreshToken); err == nil || !strings.Contains(err.Error(), "reuse detected") {
+		t.Fatalf("expected reuse to be detected, got %v", err)
+	}
+	// token hasil rotasi ikut tidak berlaku karena session sudah dicabut
+	if _, err := authUC.RefreshToken(second.RefreshToken); err == nil {
+		t.Error("refresh token of a revoked session should be rejected")
+	}
+	if _, err := authUC.VerifyAccessToken(second.Token); err == nil {
+		t.Error("access token of a revoked session should be rejected")
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		lifeTime time.Duration
+		prepare  func(t *testing.T, ucm manager.UseCaseManager, auth dto.AuthResponseDto) string
+		wantErr  string
+	}{
+		{
+			name: "unknown token",
+			prepare: func(t *testing.T, ucm manager.UseCaseManager, auth dto.AuthResponseDto) string {
+				return "bukan-token"
+			},
+			wantErr: "invalid refresh token",
+		},
+		{
+			name:     "expired",
+			lifeTime: -time.Minute,
+			prepare: func(t *testing.T, ucm manager.UseCaseManager, auth dto.AuthResponseDto) string {
+				return auth.RefreshToken
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "logged out",
+			prepare: func(t *testing.T, ucm manager.UseCaseManager, auth dto.AuthResponseDto) string {
+				claims, err := ucm.AuthUseCase().VerifyAccessToken(auth.Token)
+				mustNoErr(t, err)
+				mustNoErr(t, ucm.AuthUseCase().Logout(claims["sid"].(string)))
+				return auth.RefreshToken
+			},
+			wantErr: "invalid refresh token",
+		},
+		{
+			name: "deactivated user",
+			prepare: func(t *testing.T, ucm manager.UseCaseManager, auth dto.AuthResponseDto) string {
+				mustNoErr(t, ucm.UserUseCase().UpdateActiveUser("us-ani", false, "owner", model.RoleOwner))
+				return auth.RefreshToken
+			},
+			wantErr: "invalid refresh token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			if tt.lifeTime != 0 {
+				cfg.RefreshTokenLifeTime = tt.lifeTime
+			}
+			ucm := newUseCaseManager(t, cfg)
+			mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-ani", Username: "ani", Password: "Rahasia123"}, "system", model.RoleOwner))
+			auth, err := ucm.AuthUseCase().Login("ani", "Rahasia123", "10.0.0.7")
+			mustNoErr(t, err)
+
+			_, err = ucm.AuthUseCase().RefreshToken(tt.prepare(t, ucm, auth))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// setelah LoginBackoffAfter kali gagal username kena jeda yang berlipat dua, lalu lockout di LoginMaxAttempts
+// yang hanya bisa dibuka setelah durasi lockout habis atau di unlock owner
+func TestLoginBackoffThenLockout(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.LoginBackoffAfter = 2
+	cfg.LoginBackoffBase = 200 * time.Millisecond
+	cfg.LoginMaxAttempts = 5
+	cfg.LoginMaxIpAttempts = 100
+	cfg.LoginLockoutDuration = time.Hour
+	ucm := newUseCaseManager(t, cfg)
+	mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-ani", Username: "ani", Password: "Rahasia123"}, "system", model.RoleOwner))
+	authUC := ucm.AuthUseCase()
+
+	var tooMany *exceptions.TooManyRequestsError
+	var lastRetryAfter time.Duration
+	for failure := 1; failure < cfg.LoginMaxAttempts; failure++ {
+		if _, err := authUC.Login("ani", "tebakan", "10.0.0.7"); err == nil || errors.As(err, &tooMany) {
+			t.Fatalf("failure %d should be rejected without lockout, got %v", failure, err)
+		}
+		if failure < cfg.LoginBackoffAfter {
+			continue
+		}
+		// password yang benar pun ditolak selama jeda berjalan
+		if _, err := authUC.Login("ani", "Rahasia123", "10.0.0.7"); !errors.As(err, &tooMany) {
+			t.Fatalf("expected backoff after %d failures, got %v", failure, err)
+		}
+		maxDelay := cfg.LoginBackoffBase << (failure - cfg.LoginBackoffAfter)
+		if tooMany.RetryAfter > maxDelay || tooMany.RetryAfter <= lastRetryAfter {
+			t.Fatalf("backoff after %d failures should grow up to %v, got %v after %v", failure, maxDelay, tooMany.RetryAfter, lastRetryAfter)
+		}
+		lastRetryAfter = tooMany.RetryAfter
+		time.Sleep(tooMany.RetryAfter)
+	}
+
+	if _, err := authUC.Login("ani", "tebakan", "10.0.0.7"); !errors.As(err, &tooMany) || tooMany.RetryAfter != cfg.LoginLockoutDuration {
+		t.Fatalf("expected lockout of %v, got %v", cfg.LoginLockoutDuration, err)
+	}
+	if _, err := authUC.Login("ani", "Rahasia123", "10.0.0.7"); !errors.As(err, &tooMany) {
+		t.Fatalf("locked username should be rejected even with the right password, got %v", err)
+	}
+	lockouts, err := authUC.FindAllLockout(true)
+	mustNoErr(t, err)
+	if len(lockouts) != 1 || lockouts[0].Kind != model.LoginAttemptUsername || lockouts[0].Value != "ani" || lockouts[0].FailedCount != cfg.LoginMaxAttempts {
+		t.Fatalf("expected one active lockout for ani, got %+v", lockouts)
+	}
+
+	mustNoErr(t, authUC.UnlockLockout(lockouts[0].Id, "owner"))
+	if err := authUC.UnlockLockout(lockouts[0].Id, "owner"); err == nil {
+		t.Error("unlocking twice should be rejected")
+	}
+	if _, err := authUC.Login("ani", "Rahasia123", "10.0.0.7"); err != nil {
+		t.Errorf("login after unlock should succeed, got %v", err)
+	}
+	if active, err := authUC.FindAllLockout(true); err != nil || len(active) != 0 {
+		t.Errorf("unlocked lockout should not be active, got %+v and %v", active, err)
+	}
+}
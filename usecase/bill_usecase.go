@@ -2,18 +2,33 @@ package usecase
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/NursiNursi/laundry-apps/config"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/repository"
 	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type BillUseCase interface {
-	RegisterNewBill(payload model.Bill) error
+	RegisterNewBill(payload model.Bill, actor string) error
 	FindByIdBill(id string) (dto.BillResponseDto, error)
 	FindAllBill(requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error)
+	UpdateBillStatus(id string, status string, changedBy string) error
+	VoidBill(id string, reason string, voidedBy string) error
+	AmendBill(id string, details []model.BillDetail, actor string) error
+}
+
+// transisi status yang diperbolehkan, selain ini akan ditolak
+var billStatusTransitions = map[string][]string{
+	model.BillStatusReceived: {model.BillStatusWashing},
+	model.BillStatusWashing:  {model.BillStatusDrying},
+	model.BillStatusDrying:   {model.BillStatusIroning, model.BillStatusReady},
+	model.BillStatusIroning:  {model.BillStatusReady},
+	model.BillStatusReady:    {model.BillStatusPickedUp},
 }
 
 type billUseCase struct {
@@ -21,9 +36,11 @@ type billUseCase struct {
 	empUseCase EmployeeUseCase
 	cstUseCase CustomerUseCase
 	prdUseCase ProductUseCase
+	cfg        config.BusinessConfig
+	auditUC    AuditUseCase
 }
 
-func (b *billUseCase) RegisterNewBill(newBill model.Bill) error {
+func (b *billUseCase) RegisterNewBill(newBill model.Bill, actor string) error {
 	// get customer
 	customer, err := b.cstUseCase.FindByIdCustomer(newBill.CustomerId)
 	if err != nil {
@@ -35,20 +52,29 @@ func (b *billUseCase) RegisterNewBill(newBill model.Bill) error {
 		return fmt.Errorf("employee with ID %s not found", newBill.EmployeeId)
 	}
 	newBillDetail := make([]model.BillDetail, 0, len(newBill.BillDetails))
+	// estimasi selesai mengikuti produk yang paling lama pengerjaannya
+	var turnaroundHours int
 	for _, detail := range newBill.BillDetails {
 		// get product
 		product, err := b.prdUseCase.FindByIdProduct(detail.ProductId)
 		if err != nil {
-			return fmt.Errorf("product with ID %s not found", newBill.EmployeeId)
+			return fmt.Errorf("product with ID %s not found", detail.ProductId)
 		}
 		detail.Id = common.GenerateID()
 		detail.BillId = newBill.Id
 		detail.ProductId = product.Id
 		detail.ProductPrice = product.Price
 		newBillDetail = append(newBillDetail, detail)
+		if product.TurnaroundHours > turnaroundHours {
+			turnaroundHours = product.TurnaroundHours
+		}
 	}
-	newBill.BillDate = time.Now()
-	newBill.EntryDate = time.Now()
+	// tanggal bill mengikuti zona waktu toko supaya laporan harian tidak bergeser
+	now := time.Now().In(b.cfg.Location)
+	newBill.BillDate = now
+	newBill.EntryDate = now
+	newBill.FinishDate = common.EstimateFinishDate(newBill.EntryDate, time.Duration(turnaroundHours)*time.Hour, b.cfg.ClosedDays, b.cfg.Holidays)
+	newBill.Status = model.BillStatusReceived
 	newBill.CustomerId = customer.Id
 	newBill.EmployeeId = employee.Id
 	newBill.BillDetails = newBillDetail
@@ -57,12 +83,20 @@ func (b *billUseCase) RegisterNewBill(newBill model.Bill) error {
 	if err != nil {
 		return fmt.Errorf("failed to register new bill %v", err)
 	}
+	b.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityBill, newBill.Id, nil, newBill)
 
 	return nil
 }
 
 func (b *billUseCase) FindAllBill(requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error) {
-	return b.repo.Paging(requestPaging)
+	bills, paging, err := b.repo.Paging(requestPaging)
+	if err != nil {
+		return nil, dto.Paging{}, err
+	}
+	for i := range bills {
+		setPaymentSummary(&bills[i])
+	}
+	return bills, paging, nil
 }
 
 func (b *billUseCase) FindByIdBill(id string) (dto.BillResponseDto, error) {
@@ -79,14 +113,225 @@ func (b *billUseCase) FindByIdBill(id string) (dto.BillResponseDto, error) {
 	}
 	billResponseDto = billResponse
 	billResponseDto.TotalBill = subTotal
+	setPaymentSummary(&billResponseDto)
+
+	histories, err := b.repo.ListStatusHistory(id)
+	if err != nil {
+		return dto.BillResponseDto{}, fmt.Errorf("failed get bill status history: %v", err.Error())
+	}
+	billResponseDto.StatusHistory = histories
 	return billResponseDto, nil
 }
 
-func NewBillUseCase(repo repository.BillRepository, empUseCase EmployeeUseCase, cstUseCase CustomerUseCase, prdUseCase ProductUseCase) BillUseCase {
+func (b *billUseCase) UpdateBillStatus(id string, status string, changedBy string) error {
+	if !isKnownBillStatus(status) {
+		return fmt.Errorf("%w: status %s is not valid", exceptions.ErrInvalidBillRequest, status)
+	}
+	bill, err := b.repo.Get(id)
+	if err != nil {
+		return fmt.Errorf("%w: bill with ID %s not found", exceptions.ErrBillNotFound, id)
+	}
+
+	if !isValidBillStatusTransition(bill.Status, status) {
+		return fmt.Errorf("%w: cannot change bill status from %s to %s", exceptions.ErrBillStatusConflict, bill.Status, status)
+	}
+
+	err = b.repo.UpdateStatus(model.BillStatusHistory{
+		Id:         common.GenerateID(),
+		BillId:     bill.Id,
+		FromStatus: bill.Status,
+		ToStatus:   status,
+		ChangedBy:  changedBy,
+		ChangedAt:  time.Now().In(b.cfg.Location),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update bill status: %w", err)
+	}
+	b.auditUC.Record(changedBy, model.AuditActionStatus, model.AuditEntityBill, bill.Id, map[string]string{"status": bill.Status}, map[string]string{"status": status})
+	return nil
+}
+
+func (b *billUseCase) VoidBill(id string, reason string, voidedBy string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: reason is required field", exceptions.ErrInvalidBillRequest)
+	}
+	if voidedBy == "" {
+		return fmt.Errorf("%w: acting user is required to void a bill", exceptions.ErrInvalidBillRequest)
+	}
+
+	bill, err := b.repo.Get(id)
+	if err != nil {
+		return fmt.Errorf("%w: bill with ID %s not found", exceptions.ErrBillNotFound, id)
+	}
+	switch bill.Status {
+	case model.BillStatusCancelled:
+		return fmt.Errorf("%w: bill with ID %s is already cancelled", exceptions.ErrBillStatusConflict, id)
+	case model.BillStatusPickedUp:
+		// cucian sudah diambil customer, koreksi dilakukan lewat transaksi baru
+		return fmt.Errorf("%w: bill with ID %s has been picked up and can not be voided", exceptions.ErrBillStatusConflict, id)
+	}
+
+	err = b.repo.Void(model.BillVoid{
+		BillId:     bill.Id,
+		FromStatus: bill.Status,
+		Reason:     reason,
+		VoidedBy:   voidedBy,
+		VoidedAt:   time.Now().In(b.cfg.Location),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to void bill: %w", err)
+	}
+	b.auditUC.Record(voidedBy, model.AuditActionVoid, model.AuditEntityBill, bill.Id, map[string]string{"status": bill.Status}, map[string]string{"status": model.BillStatusCancelled, "reason": reason})
+	return nil
+}
+
+// AmendBill menambah, menghapus (qty 0) atau mengubah qty detail bill yang belum selesai.
+// Detail dengan Id mengubah baris yang sudah ada, detail tanpa Id ditambahkan sebagai baris baru.
+func (b *billUseCase) AmendBill(id string, details []model.BillDetail, actor string) error {
+	bill, err := b.repo.Get(id)
+	if err != nil {
+		return fmt.Errorf("%w: bill with ID %s not found", exceptions.ErrBillNotFound, id)
+	}
+	switch bill.Status {
+	case model.BillStatusReady, model.BillStatusPickedUp, model.BillStatusCancelled:
+		return fmt.Errorf("%w: bill with ID %s is %s and can not be amended", exceptions.ErrBillStatusConflict, id, bill.Status)
+	}
+
+	amendedDetails := make([]model.BillDetail, 0, len(bill.BillDetails))
+	for _, existing := range bill.BillDetails {
+		amendedDetails = append(amendedDetails, model.BillDetail{
+			Id:           existing.Id,
+			BillId:       bill.Id,
+			ProductId:    existing.Product.Id,
+			ProductPrice: existing.ProductPrice,
+			Qty:          existing.Qty,
+		})
+	}
+
+	// baris baru dan baris yang diubah harganya di snapshot ulang dari harga produk saat ini
+	repriced := make(map[string]bool)
+	for _, detail := range details {
+		if detail.Qty < 0 {
+			return fmt.Errorf("%w: qty can not be negative", exceptions.ErrInvalidBillRequest)
+		}
+
+		if detail.Id == "" {
+			if detail.Qty == 0 {
+				return fmt.Errorf("%w: qty is required for new product %s", exceptions.ErrInvalidBillRequest, detail.ProductId)
+			}
+			newDetail := model.BillDetail{
+				Id:        common.GenerateID(),
+				BillId:    bill.Id,
+				ProductId: detail.ProductId,
+				Qty:       detail.Qty,
+			}
+			amendedDetails = append(amendedDetails, newDetail)
+			repriced[newDetail.Id] = true
+			continue
+		}
+
+		index := -1
+		for i, amended := range amendedDetails {
+			if amended.Id == detail.Id {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("%w: bill detail with ID %s not found", exceptions.ErrInvalidBillRequest, detail.Id)
+		}
+		if detail.Qty == 0 {
+			amendedDetails = append(amendedDetails[:index], amendedDetails[index+1:]...)
+			continue
+		}
+		amendedDetails[index].Qty = detail.Qty
+		repriced[detail.Id] = true
+	}
+
+	if len(amendedDetails) == 0 {
+		return fmt.Errorf("%w: bill must have at least one item", exceptions.ErrInvalidBillRequest)
+	}
+
+	var turnaroundHours int
+	for i, detail := range amendedDetails {
+		// baris lama yang tidak diubah tetap bisa dipakai walaupun produknya sudah di soft delete,
+		// produk yang masih aktif hanya wajib untuk baris baru atau yang harganya di snapshot ulang
+		var product model.Product
+		if repriced[detail.Id] {
+			product, err = b.prdUseCase.FindByIdProduct(detail.ProductId)
+		} else {
+			product, err = b.prdUseCase.FindByIdProductIncludeDeleted(detail.ProductId)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: product with ID %s not found", exceptions.ErrInvalidBillRequest, detail.ProductId)
+		}
+		if repriced[detail.Id] {
+			amendedDetails[i].ProductPrice = product.Price
+		}
+		if product.TurnaroundHours > turnaroundHours {
+			turnaroundHours = product.TurnaroundHours
+		}
+	}
+
+	amendedBill := model.Bill{
+		Id:          bill.Id,
+		FinishDate:  common.EstimateFinishDate(bill.EntryDate, time.Duration(turnaroundHours)*time.Hour, b.cfg.ClosedDays, b.cfg.Holidays),
+		Status:      bill.Status,
+		BillDetails: amendedDetails,
+	}
+	// total tidak boleh kurang dari yang sudah dibayar, dicek di repository dalam transaksi yang sama
+	err = b.repo.UpdateDetails(amendedBill)
+	if err != nil {
+		return fmt.Errorf("failed to amend bill: %w", err)
+	}
+	before := map[string]any{"finishDate": bill.FinishDate, "billDetails": bill.BillDetails}
+	after := map[string]any{"finishDate": amendedBill.FinishDate, "billDetails": amendedDetails}
+	b.auditUC.Record(actor, model.AuditActionAmend, model.AuditEntityBill, bill.Id, before, after)
+	return nil
+}
+
+func isKnownBillStatus(status string) bool {
+	switch status {
+	case model.BillStatusReceived, model.BillStatusWashing, model.BillStatusDrying, model.BillStatusIroning,
+		model.BillStatusReady, model.BillStatusPickedUp, model.BillStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func isValidBillStatusTransition(from string, to string) bool {
+	for _, next := range billStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func NewBillUseCase(repo repository.BillRepository, empUseCase EmployeeUseCase, cstUseCase CustomerUseCase, prdUseCase ProductUseCase, cfg config.BusinessConfig, auditUC AuditUseCase) BillUseCase {
 	return &billUseCase{
 		repo:       repo,
 		empUseCase: empUseCase,
 		cstUseCase: cstUseCase,
 		prdUseCase: prdUseCase,
+		cfg:        cfg,
+		auditUC:    auditUC,
+	}
+}
+
+// setPaymentSummary menghitung sisa tagihan dan status pembayaran dari TotalBill dan AmountPaid
+func setPaymentSummary(bill *dto.BillResponseDto) {
+	bill.BalanceDue = bill.TotalBill - bill.AmountPaid
+	if bill.Status == model.BillStatusCancelled {
+		// bill yang di void sudah tidak punya tagihan
+		bill.BalanceDue = 0
+	}
+	switch {
+	case bill.AmountPaid <= 0:
+		bill.PaymentStatus = model.PaymentStatusUnpaid
+	case bill.BalanceDue > 0:
+		bill.PaymentStatus = model.PaymentStatusPartiallyPaid
+	default:
+		bill.PaymentStatus = model.PaymentStatusPaid
 	}
 }
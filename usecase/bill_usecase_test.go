@@ -0,0 +1,262 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+// seedBill membuat master data dan satu bill b1: 3 x Cuci Kering (7000) = 21000
+func seedBill(t *testing.T, ucm manager.UseCaseManager) {
+	t.Helper()
+	mustNoErr(t, ucm.UomUseCase().RegisterNewUom(model.Uom{Id: "u1", Name: "Kg"}, "system"))
+	mustNoErr(t, ucm.ProductUseCase().RegisterNewProduct(model.Product{Id: "p1", Name: "Cuci Kering", Price: 7000, TurnaroundHours: 48, Uom: model.Uom{Id: "u1"}}, "system"))
+	mustNoErr(t, ucm.ProductUseCase().RegisterNewProduct(model.Product{Id: "p2", Name: "Bed Cover", Price: 25000, TurnaroundHours: 72, Uom: model.Uom{Id: "u1"}}, "system"))
+	mustNoErr(t, ucm.CustomerUseCase().RegisterNewCustomer(model.Customer{Id: "c1", Name: "Ani", PhoneNumber: "0812", Address: "Jl. Melati"}, "system"))
+	mustNoErr(t, ucm.EmployeeUseCase().RegisterNewEmployee(model.Employee{Id: "e1", Name: "Budi", PhoneNumber: "0811", Address: "Jl. Mawar"}, "system"))
+	mustNoErr(t, ucm.BillUseCase().RegisterNewBill(model.Bill{
+		Id: "b1", CustomerId: "c1", EmployeeId: "e1",
+		BillDetails: []model.BillDetail{{ProductId: "p1", Qty: 3}},
+	}, "kasir"))
+}
+
+// setBillStatus menjalankan transisi yang sah satu per satu sampai status yang diminta
+func setBillStatus(t *testing.T, ucm manager.UseCaseManager, id string, path ...string) {
+	t.Helper()
+	for _, status := range path {
+		mustNoErr(t, ucm.BillUseCase().UpdateBillStatus(id, status, "kasir"))
+	}
+}
+
+func TestUpdateBillStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []string
+		to      string
+		billId  string
+		wantErr error
+	}{
+		{name: "received to washing", to: model.BillStatusWashing},
+		{name: "drying to ironing", path: []string{model.BillStatusWashing, model.BillStatusDrying}, to: model.BillStatusIroning},
+		{name: "drying skips ironing", path: []string{model.BillStatusWashing, model.BillStatusDrying}, to: model.BillStatusReady},
+		{name: "ready to picked up", path: []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady}, to: model.BillStatusPickedUp},
+		{name: "picked up before ready", to: model.BillStatusPickedUp, wantErr: exceptions.ErrBillStatusConflict},
+		{name: "backwards", path: []string{model.BillStatusWashing}, to: model.BillStatusReceived, wantErr: exceptions.ErrBillStatusConflict},
+		{name: "same status", to: model.BillStatusReceived, wantErr: exceptions.ErrBillStatusConflict},
+		{name: "cancel through status change", to: model.BillStatusCancelled, wantErr: exceptions.ErrBillStatusConflict},
+		{name: "after picked up", path: []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady, model.BillStatusPickedUp}, to: model.BillStatusWashing, wantErr: exceptions.ErrBillStatusConflict},
+		{name: "unknown status", to: "lost", wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "unknown bill", to: model.BillStatusWashing, billId: "missing", wantErr: exceptions.ErrBillNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucm := newUseCaseManager(t, newTestConfig(t))
+			seedBill(t, ucm)
+			setBillStatus(t, ucm, "b1", tt.path...)
+			billId := tt.billId
+			if billId == "" {
+				billId = "b1"
+			}
+
+			err := ucm.BillUseCase().UpdateBillStatus(billId, tt.to, "kasir")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			mustNoErr(t, err)
+			bill, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+			if bill.Status != tt.to {
+				t.Errorf("expected status %s, got %s", tt.to, bill.Status)
+			}
+			// setiap transisi dicatat beserta siapa yang mengubah
+			last := bill.StatusHistory[len(bill.StatusHistory)-1]
+			if len(bill.StatusHistory) != len(tt.path)+1 || last.ToStatus != tt.to || last.ChangedBy != "kasir" {
+				t.Errorf("unexpected status history %+v", bill.StatusHistory)
+			}
+		})
+	}
+}
+
+func TestVoidBill(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []string
+		billId  string
+		reason  string
+		wantErr error
+	}{
+		{name: "received", reason: "salah input"},
+		{name: "ready", path: []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady}, reason: "customer batal"},
+		{name: "empty reason", reason: "  ", wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "unknown bill", billId: "missing", reason: "salah input", wantErr: exceptions.ErrBillNotFound},
+		{name: "picked up", path: []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady, model.BillStatusPickedUp}, reason: "salah input", wantErr: exceptions.ErrBillStatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucm := newUseCaseManager(t, newTestConfig(t))
+			seedBill(t, ucm)
+			_, err := ucm.PaymentUseCase().RegisterNewPayment(model.Payment{BillId: "b1", Amount: 10000, Method: model.PaymentMethodCash, ReceivedBy: "kasir"})
+			mustNoErr(t, err)
+			setBillStatus(t, ucm, "b1", tt.path...)
+			billId := tt.billId
+			if billId == "" {
+				billId = "b1"
+			}
+
+			err = ucm.BillUseCase().VoidBill(billId, tt.reason, "owner")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			mustNoErr(t, err)
+			bill, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+			if bill.Status != model.BillStatusCancelled || bill.VoidReason != tt.reason || bill.VoidedBy != "owner" || bill.AmountPaid != 0 || bill.BalanceDue != 0 {
+				t.Errorf("unexpected voided bill %+v", bill)
+			}
+			// pembayaran asli tetap ada, dibalik dengan baris bernominal negatif
+			payments, err := ucm.PaymentUseCase().FindAllPaymentByBill("b1")
+			mustNoErr(t, err)
+			if len(payments) != 2 || payments[1].Amount != -10000 || payments[1].ReversalOf != payments[0].Id {
+				t.Errorf("unexpected payments %+v", payments)
+			}
+
+			if err := ucm.BillUseCase().VoidBill("b1", tt.reason, "owner"); !errors.Is(err, exceptions.ErrBillStatusConflict) {
+				t.Errorf("second void should conflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAmendBill(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+		// paid dibayar sebelum amend, b1 awalnya 3 x 7000 = 21000
+		paid int
+		// details dibuat dari ID detail pertama b1
+		details   func(detailId string) []model.BillDetail
+		wantErr   error
+		wantTotal int
+	}{
+		{name: "change qty", details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 5}}
+		}, wantTotal: 35000},
+		{name: "add product", path: []string{model.BillStatusWashing}, details: func(string) []model.BillDetail {
+			return []model.BillDetail{{ProductId: "p2", Qty: 1}}
+		}, wantTotal: 46000},
+		{name: "replace product", details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 0}, {ProductId: "p2", Qty: 2}}
+		}, wantTotal: 50000},
+		{name: "down to amount paid", paid: 14000, details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 2}}
+		}, wantTotal: 14000},
+		{name: "below amount paid", paid: 15000, details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 2}}
+		}, wantErr: exceptions.ErrBillTotalBelowAmountPaid},
+		{name: "remove every item", details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 0}}
+		}, wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "negative qty", details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: -1}}
+		}, wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "unknown detail", details: func(string) []model.BillDetail {
+			return []model.BillDetail{{Id: "missing", Qty: 1}}
+		}, wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "unknown product", details: func(string) []model.BillDetail {
+			return []model.BillDetail{{ProductId: "missing", Qty: 1}}
+		}, wantErr: exceptions.ErrInvalidBillRequest},
+		{name: "ready", path: []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady}, details: func(detailId string) []model.BillDetail {
+			return []model.BillDetail{{Id: detailId, Qty: 5}}
+		}, wantErr: exceptions.ErrBillStatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucm := newUseCaseManager(t, newTestConfig(t))
+			seedBill(t, ucm)
+			if tt.paid > 0 {
+				_, err := ucm.PaymentUseCase().RegisterNewPayment(model.Payment{BillId: "b1", Amount: tt.paid, Method: model.PaymentMethodCash, ReceivedBy: "kasir"})
+				mustNoErr(t, err)
+			}
+			setBillStatus(t, ucm, "b1", tt.path...)
+			bill, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+
+			err = ucm.BillUseCase().AmendBill("b1", tt.details(bill.BillDetails[0].Id), "kasir")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			mustNoErr(t, err)
+			amended, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+			if amended.TotalBill != tt.wantTotal || amended.BalanceDue != tt.wantTotal-tt.paid {
+				t.Errorf("expected total %d, got %+v", tt.wantTotal, amended)
+			}
+		})
+	}
+}
+
+// baris yang diubah qty nya memakai harga produk saat amend, baris lain tetap memakai harga lama
+func TestAmendBillRepricesChangedLines(t *testing.T) {
+	ucm := newUseCaseManager(t, newTestConfig(t))
+	seedBill(t, ucm)
+	mustNoErr(t, ucm.BillUseCase().AmendBill("b1", []model.BillDetail{{ProductId: "p2", Qty: 1}}, "kasir"))
+	product, err := ucm.ProductUseCase().FindByIdProduct("p1")
+	mustNoErr(t, err)
+	product.Price = 8000
+	_, err = ucm.ProductUseCase().UpdateProduct(product, "owner")
+	mustNoErr(t, err)
+
+	bill, err := ucm.BillUseCase().FindByIdBill("b1")
+	mustNoErr(t, err)
+	var cuciKering string
+	for _, detail := range bill.BillDetails {
+		if detail.Product.Id == "p1" {
+			cuciKering = detail.Id
+		}
+	}
+	mustNoErr(t, ucm.BillUseCase().AmendBill("b1", []model.BillDetail{{Id: cuciKering, Qty: 4}}, "kasir"))
+	bill, err = ucm.BillUseCase().FindByIdBill("b1")
+	mustNoErr(t, err)
+	if bill.TotalBill != 4*8000+25000 {
+		t.Errorf("changed line should use the current price, got total %d", bill.TotalBill)
+	}
+}
+
+func TestRegisterNewBill(t *testing.T) {
+	cfg := newTestConfig(t)
+	ucm := newUseCaseManager(t, cfg)
+	seedBill(t, ucm)
+
+	// estimasi selesai mengikuti produk yang paling lama, sampai level jam
+	mustNoErr(t, ucm.BillUseCase().RegisterNewBill(model.Bill{
+		Id: "b2", CustomerId: "c1", EmployeeId: "e1",
+		BillDetails: []model.BillDetail{{ProductId: "p1", Qty: 1}, {ProductId: "p2", Qty: 1}},
+	}, "kasir"))
+	bill, err := ucm.BillUseCase().FindByIdBill("b2")
+	mustNoErr(t, err)
+	if got := bill.FinishDate.Sub(bill.EntryDate); got != 72*time.Hour {
+		t.Errorf("finish date should be 72 hours after entry, got %v", got)
+	}
+
+	err = ucm.BillUseCase().RegisterNewBill(model.Bill{
+		Id: "b3", CustomerId: "c1", EmployeeId: "e1",
+		BillDetails: []model.BillDetail{{ProductId: "missing", Qty: 1}},
+	}, "kasir")
+	if err == nil || !strings.Contains(err.Error(), "product with ID missing") {
+		t.Errorf("unknown product should be reported with its ID, got %v", err)
+	}
+}
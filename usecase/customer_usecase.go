@@ -6,22 +6,29 @@ import (
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type CustomerUseCase interface {
-	RegisterNewCustomer(payload model.Customer) error
+	RegisterNewCustomer(payload model.Customer, actor string) error
 	FindAllCustomer(requesPaging dto.PaginationParam) ([]model.Customer, dto.Paging, error)
 	FindByIdCustomer(id string) (model.Customer, error)
-	UpdateCustomer(payload model.Customer) error
-	DeleteCustomer(id string) error
+	FindByIdCustomerIncludeDeleted(id string) (model.Customer, error)
+	UpdateCustomer(payload model.Customer, actor string) (model.Customer, error)
+	DeleteCustomer(id string, actor string) error
+	RestoreCustomer(id string, actor string) error
+	FindCustomerBills(id string, requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error)
+	GetCustomerSummary(id string) (dto.CustomerSummaryDto, error)
 }
 
 type customerUseCase struct {
-	repo repository.CustomerRepository
+	repo     repository.CustomerRepository
+	billRepo repository.BillRepository
+	auditUC  AuditUseCase
 }
 
 // DeleteCustomer implements CustomerUseCase.
-func (c *customerUseCase) DeleteCustomer(id string) error {
+func (c *customerUseCase) DeleteCustomer(id string, actor string) error {
 	customer, err := c.FindByIdCustomer(id)
 	if err != nil {
 		return fmt.Errorf("customer with ID %s not found", id)
@@ -31,6 +38,27 @@ func (c *customerUseCase) DeleteCustomer(id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete customer: %v", err.Error())
 	}
+	c.auditUC.Record(actor, model.AuditActionDelete, model.AuditEntityCustomer, customer.Id, customer, nil)
+	return nil
+}
+
+// RestoreCustomer implements CustomerUseCase.
+func (c *customerUseCase) RestoreCustomer(id string, actor string) error {
+	customer, err := c.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return fmt.Errorf("customer with ID %s not found", id)
+	}
+	if customer.DeletedAt == nil {
+		return fmt.Errorf("customer with ID %s is not deleted", id)
+	}
+
+	err = c.repo.Restore(customer.Id)
+	if err != nil {
+		return fmt.Errorf("failed to restore customer: %v", err)
+	}
+	after := customer
+	after.DeletedAt = nil
+	c.auditUC.Record(actor, model.AuditActionRestore, model.AuditEntityCustomer, customer.Id, customer, after)
 	return nil
 }
 
@@ -44,38 +72,103 @@ func (c *customerUseCase) FindByIdCustomer(id string) (model.Customer, error) {
 	return c.repo.Get(id)
 }
 
+// FindByIdCustomerIncludeDeleted implements CustomerUseCase.
+func (c *customerUseCase) FindByIdCustomerIncludeDeleted(id string) (model.Customer, error) {
+	return c.repo.GetIncludeDeleted(id)
+}
+
 // RegisterNewCustomer implements CustomerUseCase.
-func (c *customerUseCase) RegisterNewCustomer(payload model.Customer) error {
+func (c *customerUseCase) RegisterNewCustomer(payload model.Customer, actor string) error {
 	if payload.Name == "" || payload.PhoneNumber == "" {
 		return fmt.Errorf("name, phone number are required fields")
 	}
-	customer, _ := c.repo.GetPhoneNumber(payload.PhoneNumber)
-	if customer.PhoneNumber == payload.PhoneNumber {
-		return fmt.Errorf("customer with phone number %s already exists", payload.PhoneNumber)
+	if err := c.checkPhoneNumber(payload); err != nil {
+		return err
 	}
 	err := c.repo.Create(payload)
 	if err != nil {
 		return fmt.Errorf("failed to create customer: %v", err.Error())
 	}
+	c.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityCustomer, payload.Id, nil, payload)
 	return nil
 }
 
 // UpdateCustomer implements CustomerUseCase.
-func (c *customerUseCase) UpdateCustomer(payload model.Customer) error {
+func (c *customerUseCase) UpdateCustomer(payload model.Customer, actor string) (model.Customer, error) {
 	if payload.Name == "" || payload.PhoneNumber == "" {
-		return fmt.Errorf("name, phone number are required fields")
+		return model.Customer{}, fmt.Errorf("name, phone number are required fields")
+	}
+	before, err := c.FindByIdCustomer(payload.Id)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("customer with ID %s not found", payload.Id)
 	}
-	customer, _ := c.repo.GetPhoneNumber(payload.PhoneNumber)
-	if customer.PhoneNumber == payload.PhoneNumber && customer.Id != payload.Id {
-		return fmt.Errorf("customer with phone number %s already exists", payload.PhoneNumber)
+	if before.Version != payload.Version {
+		return model.Customer{}, exceptions.ErrVersionConflict
 	}
-	err := c.repo.Update(payload)
+	if err := c.checkPhoneNumber(payload); err != nil {
+		return model.Customer{}, err
+	}
+	err = c.repo.Update(payload)
 	if err != nil {
-		return fmt.Errorf("failed to update customer: %v", err.Error())
+		return model.Customer{}, fmt.Errorf("failed to update customer: %w", err)
 	}
-	return nil
+	updated, err := c.repo.Get(payload.Id)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("failed to get updated customer: %v", err)
+	}
+	c.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityCustomer, payload.Id, before, updated)
+	return updated, nil
+}
+
+// FindCustomerBills implements CustomerUseCase.
+func (c *customerUseCase) FindCustomerBills(id string, requestPaging dto.PaginationParam) ([]dto.BillResponseDto, dto.Paging, error) {
+	// riwayat customer yang sudah di soft delete tetap bisa dilihat
+	customer, err := c.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return nil, dto.Paging{}, fmt.Errorf("customer with ID %s not found", id)
+	}
+
+	requestPaging.CustomerId = customer.Id
+	bills, paging, err := c.billRepo.Paging(requestPaging)
+	if err != nil {
+		return nil, dto.Paging{}, fmt.Errorf("failed to get customer bills: %w", err)
+	}
+	for i := range bills {
+		setPaymentSummary(&bills[i])
+	}
+	return bills, paging, nil
+}
+
+// GetCustomerSummary implements CustomerUseCase.
+func (c *customerUseCase) GetCustomerSummary(id string) (dto.CustomerSummaryDto, error) {
+	customer, err := c.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return dto.CustomerSummaryDto{}, fmt.Errorf("customer with ID %s not found", id)
+	}
+
+	summary, err := c.billRepo.GetCustomerSummary(customer.Id)
+	if err != nil {
+		return dto.CustomerSummaryDto{}, fmt.Errorf("failed to get customer summary: %v", err)
+	}
+	summary.Customer = customer
+	if summary.TotalOrders > 0 {
+		summary.AverageOrderValue = summary.TotalSpent / summary.TotalOrders
+	}
+	return summary, nil
+}
+
+// checkPhoneNumber ikut mencari customer yang sudah di soft delete karena nomor telepon tetap unik di database
+func (c *customerUseCase) checkPhoneNumber(payload model.Customer) error {
+	customer, err := c.repo.GetPhoneNumber(payload.PhoneNumber, true)
+	if err != nil || customer.Id == payload.Id {
+		return nil
+	}
+	if customer.DeletedAt != nil {
+		return fmt.Errorf("phone number %s belongs to deleted customer %s, restore it instead", payload.PhoneNumber, customer.Id)
+	}
+	return fmt.Errorf("customer with phone number %s already exists", payload.PhoneNumber)
 }
 
-func NewCustomerUseCase(repo repository.CustomerRepository) CustomerUseCase {
-	return &customerUseCase{repo: repo}
+func NewCustomerUseCase(repo repository.CustomerRepository, billRepo repository.BillRepository, auditUC AuditUseCase) CustomerUseCase {
+	return &customerUseCase{repo: repo, billRepo: billRepo, auditUC: auditUC}
 }
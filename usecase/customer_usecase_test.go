@@ -0,0 +1,29 @@
+package usecase_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/model"
+)
+
+func TestRegisterCustomerWithPhoneOfDeletedCustomer(t *testing.T) {
+	ucm := newUseCaseManager(t, newTestConfig(t))
+	customerUC := ucm.CustomerUseCase()
+	mustNoErr(t, customerUC.RegisterNewCustomer(model.Customer{Id: "c1", Name: "Ani", PhoneNumber: "0812"}, "kasir"))
+	mustNoErr(t, customerUC.DeleteCustomer("c1", "owner"))
+
+	err := customerUC.RegisterNewCustomer(model.Customer{Id: "c2", Name: "Ani", PhoneNumber: "0812"}, "kasir")
+	if err == nil || !strings.Contains(err.Error(), "restore it instead") {
+		t.Errorf("expected phone number of deleted customer to be rejected, got %v", err)
+	}
+
+	if _, err := customerUC.FindByIdCustomer("c1"); err == nil {
+		t.Error("deleted customer should not be found without includeDeleted")
+	}
+	deleted, err := customerUC.FindByIdCustomerIncludeDeleted("c1")
+	mustNoErr(t, err)
+	if deleted.DeletedAt == nil {
+		t.Errorf("expected deleted customer, got %+v", deleted)
+	}
+}
@@ -6,21 +6,25 @@ import (
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type EmployeeUseCase interface {
-	RegisterNewEmployee(payload model.Employee) error
+	RegisterNewEmployee(payload model.Employee, actor string) error
 	FindAllEmployee(requesPaging dto.PaginationParam) ([]model.Employee, dto.Paging, error)
 	FindByIdEmployee(id string) (model.Employee, error)
-	UpdateEmployee(payload model.Employee) error
-	DeleteEmployee(id string) error
+	FindByIdEmployeeIncludeDeleted(id string) (model.Employee, error)
+	UpdateEmployee(payload model.Employee, actor string) (model.Employee, error)
+	DeleteEmployee(id string, actor string) error
+	RestoreEmployee(id string, actor string) error
 }
 
 type employeeUseCase struct {
-	repo repository.EmployeeRepository
+	repo    repository.EmployeeRepository
+	auditUC AuditUseCase
 }
 
-func (e *employeeUseCase) DeleteEmployee(id string) error {
+func (e *employeeUseCase) DeleteEmployee(id string, actor string) error {
 	employee, err := e.FindByIdEmployee(id)
 	if err != nil {
 		return fmt.Errorf("employee with ID %s not found", id)
@@ -30,6 +34,26 @@ func (e *employeeUseCase) DeleteEmployee(id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete customer: %v", err.Error())
 	}
+	e.auditUC.Record(actor, model.AuditActionDelete, model.AuditEntityEmployee, employee.Id, employee, nil)
+	return nil
+}
+
+func (e *employeeUseCase) RestoreEmployee(id string, actor string) error {
+	employee, err := e.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return fmt.Errorf("employee with ID %s not found", id)
+	}
+	if employee.DeletedAt == nil {
+		return fmt.Errorf("employee with ID %s is not deleted", id)
+	}
+
+	err = e.repo.Restore(employee.Id)
+	if err != nil {
+		return fmt.Errorf("failed to restore employee: %v", err)
+	}
+	after := employee
+	after.DeletedAt = nil
+	e.auditUC.Record(actor, model.AuditActionRestore, model.AuditEntityEmployee, employee.Id, employee, after)
 	return nil
 }
 
@@ -41,36 +65,63 @@ func (e *employeeUseCase) FindByIdEmployee(id string) (model.Employee, error) {
 	return e.repo.Get(id)
 }
 
-func (e *employeeUseCase) RegisterNewEmployee(payload model.Employee) error {
+func (e *employeeUseCase) FindByIdEmployeeIncludeDeleted(id string) (model.Employee, error) {
+	return e.repo.GetIncludeDeleted(id)
+}
+
+func (e *employeeUseCase) RegisterNewEmployee(payload model.Employee, actor string) error {
 	if payload.Name == "" || payload.PhoneNumber == "" {
 		return fmt.Errorf("name, phone number are required fields")
 	}
-	employee, _ := e.repo.GetPhoneNumber(payload.PhoneNumber)
-	if employee.PhoneNumber == payload.PhoneNumber {
-		return fmt.Errorf("employee with phone number %s already exists", payload.PhoneNumber)
+	if err := e.checkPhoneNumber(payload); err != nil {
+		return err
 	}
 	err := e.repo.Create(payload)
 	if err != nil {
 		return fmt.Errorf("failed to create employee: %v", err.Error())
 	}
+	e.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityEmployee, payload.Id, nil, payload)
 	return nil
 }
 
-func (e *employeeUseCase) UpdateEmployee(payload model.Employee) error {
+func (e *employeeUseCase) UpdateEmployee(payload model.Employee, actor string) (model.Employee, error) {
 	if payload.Name == "" || payload.PhoneNumber == "" {
-		return fmt.Errorf("name, phone number are required fields")
+		return model.Employee{}, fmt.Errorf("name, phone number are required fields")
+	}
+	before, err := e.FindByIdEmployee(payload.Id)
+	if err != nil {
+		return model.Employee{}, fmt.Errorf("employee with ID %s not found", payload.Id)
 	}
-	employee, _ := e.repo.GetPhoneNumber(payload.PhoneNumber)
-	if employee.PhoneNumber == payload.PhoneNumber && employee.Id != payload.Id {
-		return fmt.Errorf("employee with phone number %s already exists", payload.PhoneNumber)
+	if before.Version != payload.Version {
+		return model.Employee{}, exceptions.ErrVersionConflict
 	}
-	err := e.repo.Update(payload)
+	if err := e.checkPhoneNumber(payload); err != nil {
+		return model.Employee{}, err
+	}
+	err = e.repo.Update(payload)
 	if err != nil {
-		return fmt.Errorf("failed to update employee: %v", err.Error())
+		return model.Employee{}, fmt.Errorf("failed to update employee: %w", err)
 	}
-	return nil
+	updated, err := e.repo.Get(payload.Id)
+	if err != nil {
+		return model.Employee{}, fmt.Errorf("failed to get updated employee: %v", err)
+	}
+	e.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityEmployee, payload.Id, before, updated)
+	return updated, nil
+}
+
+// checkPhoneNumber menolak nomor yang sudah dipakai employee lain, termasuk yang sudah di soft delete
+func (e *employeeUseCase) checkPhoneNumber(payload model.Employee) error {
+	employee, err := e.repo.GetPhoneNumber(payload.PhoneNumber, true)
+	if err != nil || employee.Id == payload.Id {
+		return nil
+	}
+	if employee.DeletedAt != nil {
+		return fmt.Errorf("phone number %s belongs to deleted employee %s, restore it instead", payload.PhoneNumber, employee.Id)
+	}
+	return fmt.Errorf("employee with phone number %s already exists", payload.PhoneNumber)
 }
 
-func NewEmployeeUseCase(repo repository.EmployeeRepository) EmployeeUseCase {
-	return &employeeUseCase{repo: repo}
+func NewEmployeeUseCase(repo repository.EmployeeRepository, auditUC AuditUseCase) EmployeeUseCase {
+	return &employeeUseCase{repo: repo, auditUC: auditUC}
 }
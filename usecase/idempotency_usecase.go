@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type IdempotencyUseCase interface {
+	Begin(username string, key string, requestHash string) (model.IdempotencyKey, bool, error)
+	Complete(username string, key string, statusCode int, contentType string, responseBody []byte) error
+	Abort(username string, key string) error
+	// DeleteExpired menghapus key yang sudah lewat IdempotencyKeyLifeTime, dijalankan berkala oleh server
+	DeleteExpired() error
+}
+
+type idempotencyUseCase struct {
+	repo repository.IdempotencyRepository
+	cfg  config.ApiConfig
+}
+
+// Begin implements IdempotencyUseCase.
+// mengembalikan true kalau request ini yang pertama memakai key dan harus diproses,
+// selain itu mengembalikan data key yang sudah tersimpan untuk di replay
+func (i *idempotencyUseCase) Begin(username string, key string, requestHash string) (model.IdempotencyKey, bool, error) {
+	now := time.Now().UTC()
+	payload := model.IdempotencyKey{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}
+	created, err := i.repo.Create(payload)
+	if err != nil {
+		return model.IdempotencyKey{}, false, fmt.Errorf("failed to save idempotency key: %v", err)
+	}
+	if created {
+		return payload, true, nil
+	}
+
+	existing, err := i.repo.Get(username, key)
+	if err != nil {
+		return model.IdempotencyKey{}, false, fmt.Errorf("failed to get idempotency key: %v", err)
+	}
+	// key kadaluarsa yang belum terhapus oleh DeleteExpired diperlakukan seperti request baru
+	if existing.CreatedAt.Before(now.Add(-i.cfg.IdempotencyKeyLifeTime)) {
+		if err := i.repo.Delete(username, key); err != nil {
+			return model.IdempotencyKey{}, false, fmt.Errorf("failed to delete expired idempotency key: %v", err)
+		}
+		created, err := i.repo.Create(payload)
+		if err != nil {
+			return model.IdempotencyKey{}, false, fmt.Errorf("failed to save idempotency key: %v", err)
+		}
+		if created {
+			return payload, true, nil
+		}
+		// request lain dengan key yang sama lebih dulu membuat ulang key
+		if existing, err = i.repo.Get(username, key); err != nil {
+			return model.IdempotencyKey{}, false, fmt.Errorf("failed to get idempotency key: %v", err)
+		}
+	}
+	if existing.RequestHash != requestHash {
+		return model.IdempotencyKey{}, false, exceptions.ErrIdempotencyKeyReused
+	}
+	return existing, false, nil
+}
+
+// Complete implements IdempotencyUseCase.
+func (i *idempotencyUseCase) Complete(username string, key string, statusCode int, contentType string, responseBody []byte) error {
+	return i.repo.Complete(model.IdempotencyKey{
+		Username:     username,
+		Key:          key,
+		StatusCode:   statusCode,
+		ContentType:  contentType,
+		ResponseBody: responseBody,
+	})
+}
+
+// Abort implements IdempotencyUseCase.
+// key dilepas supaya client boleh mengulang request yang gagal di sisi server
+func (i *idempotencyUseCase) Abort(username string, key string) error {
+	return i.repo.Delete(username, key)
+}
+
+// DeleteExpired implements IdempotencyUseCase.
+func (i *idempotencyUseCase) DeleteExpired() error {
+	return i.repo.DeleteExpired(time.Now().UTC().Add(-i.cfg.IdempotencyKeyLifeTime))
+}
+
+func NewIdempotencyUseCase(repo repository.IdempotencyRepository, cfg config.ApiConfig) IdempotencyUseCase {
+	return &idempotencyUseCase{repo: repo, cfg: cfg}
+}
@@ -0,0 +1,95 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+func TestIdempotencyBegin(t *testing.T) {
+	tests := []struct {
+		name        string
+		lifeTime    time.Duration
+		complete    bool
+		abort       bool
+		username    string
+		requestHash string
+		wantErr     error
+		wantStarted bool
+		wantStatus  int
+	}{
+		// request pertama masih diproses, middleware membalas 409
+		{name: "in flight", requestHash: "hash-a", wantStatus: 0},
+		{name: "replay completed", complete: true, requestHash: "hash-a", wantStatus: 201},
+		{name: "key reused with another body", complete: true, requestHash: "hash-b", wantErr: exceptions.ErrIdempotencyKeyReused},
+		{name: "key reused while in flight", requestHash: "hash-b", wantErr: exceptions.ErrIdempotencyKeyReused},
+		{name: "same key from another user", complete: true, username: "budi", requestHash: "hash-b", wantStarted: true},
+		{name: "aborted key", abort: true, requestHash: "hash-a", wantStarted: true},
+		{name: "expired key", lifeTime: -time.Minute, complete: true, requestHash: "hash-b", wantStarted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			if tt.lifeTime != 0 {
+				cfg.IdempotencyKeyLifeTime = tt.lifeTime
+			}
+			idempotencyUC := newUseCaseManager(t, cfg).IdempotencyUseCase()
+			_, started, err := idempotencyUC.Begin("ani", "key-1", "hash-a")
+			mustNoErr(t, err)
+			if !started {
+				t.Fatal("first request should be started")
+			}
+			if tt.complete {
+				mustNoErr(t, idempotencyUC.Complete("ani", "key-1", 201, "application/json", []byte(`{"id":"b1"}`)))
+			}
+			if tt.abort {
+				mustNoErr(t, idempotencyUC.Abort("ani", "key-1"))
+			}
+
+			username := tt.username
+			if username == "" {
+				username = "ani"
+			}
+			stored, started, err := idempotencyUC.Begin(username, "key-1", tt.requestHash)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			mustNoErr(t, err)
+			if started != tt.wantStarted {
+				t.Fatalf("expected started %v, got %v", tt.wantStarted, started)
+			}
+			if started {
+				return
+			}
+			if stored.StatusCode != tt.wantStatus {
+				t.Errorf("expected stored status %d, got %d", tt.wantStatus, stored.StatusCode)
+			}
+			if tt.wantStatus != 0 && (stored.ContentType != "application/json" || string(stored.ResponseBody) != `{"id":"b1"}`) {
+				t.Errorf("unexpected stored response %+v", stored)
+			}
+		})
+	}
+}
+
+func TestIdempotencyDeleteExpired(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.IdempotencyKeyLifeTime = -time.Minute
+	ucm := newUseCaseManager(t, cfg)
+	_, _, err := ucm.IdempotencyUseCase().Begin("ani", "key-1", "hash-a")
+	mustNoErr(t, err)
+	mustNoErr(t, ucm.IdempotencyUseCase().Complete("ani", "key-1", 201, "application/json", []byte(`{}`)))
+	mustNoErr(t, ucm.IdempotencyUseCase().DeleteExpired())
+
+	// dengan umur key yang panjang, key yang tidak terhapus akan ditolak sebagai key yang dipakai ulang
+	cfg.IdempotencyKeyLifeTime = time.Hour
+	_, started, err := ucm.IdempotencyUseCase().Begin("ani", "key-1", "hash-b")
+	mustNoErr(t, err)
+	if !started {
+		t.Error("expired key should be deleted")
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/common"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+type PaymentUseCase interface {
+	RegisterNewPayment(payload model.Payment) (model.Payment, error)
+	FindAllPaymentByBill(billId string) ([]model.Payment, error)
+}
+
+type paymentUseCase struct {
+	repo   repository.PaymentRepository
+	billUC BillUseCase
+}
+
+// RegisterNewPayment implements PaymentUseCase.
+func (p *paymentUseCase) RegisterNewPayment(payload model.Payment) (model.Payment, error) {
+	if payload.Amount <= 0 {
+		return model.Payment{}, fmt.Errorf("amount must be greater than zero")
+	}
+	if !isValidPaymentMethod(payload.Method) {
+		return model.Payment{}, fmt.Errorf("payment method %s is not supported", payload.Method)
+	}
+
+	bill, err := p.billUC.FindByIdBill(payload.BillId)
+	if err != nil {
+		return model.Payment{}, fmt.Errorf("bill with ID %s not found", payload.BillId)
+	}
+	if bill.Status == model.BillStatusCancelled {
+		return model.Payment{}, fmt.Errorf("bill with ID %s is cancelled", payload.BillId)
+	}
+	if bill.BalanceDue <= 0 {
+		return model.Payment{}, fmt.Errorf("bill with ID %s is already paid", payload.BillId)
+	}
+	if payload.Amount > bill.BalanceDue {
+		return model.Payment{}, fmt.Errorf("%w: amount %d, balance due %d", exceptions.ErrPaymentExceedsBalance, payload.Amount, bill.BalanceDue)
+	}
+
+	payload.Id = common.GenerateID()
+	payload.PaymentDate = time.Now()
+	// sisa tagihan dicek ulang di repository karena bisa berubah oleh pembayaran lain setelah cek di atas
+	err = p.repo.Create(payload)
+	if err != nil {
+		return model.Payment{}, fmt.Errorf("failed to register new payment: %w", err)
+	}
+	return payload, nil
+}
+
+// FindAllPaymentByBill implements PaymentUseCase.
+func (p *paymentUseCase) FindAllPaymentByBill(billId string) ([]model.Payment, error) {
+	return p.repo.ListByBillId(billId)
+}
+
+func isValidPaymentMethod(method string) bool {
+	switch method {
+	case model.PaymentMethodCash, model.PaymentMethodTransfer, model.PaymentMethodQris:
+		return true
+	}
+	return false
+}
+
+func NewPaymentUseCase(repo repository.PaymentRepository, billUC BillUseCase) PaymentUseCase {
+	return &paymentUseCase{repo: repo, billUC: billUC}
+}
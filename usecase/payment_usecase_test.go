@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+// total bill b1 dari seedBill adalah 21000, pembayaran terakhir di amounts adalah yang diperiksa errornya
+func TestRegisterNewPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		amounts     []int
+		method      string
+		void        bool
+		wantErr     error
+		wantErrText string
+		wantPaid    int
+		wantStatus  string
+	}{
+		{name: "full payment", amounts: []int{21000}, wantPaid: 21000, wantStatus: model.PaymentStatusPaid},
+		{name: "partial payments", amounts: []int{5000, 6000}, wantPaid: 11000, wantStatus: model.PaymentStatusPartiallyPaid},
+		{name: "settle remaining balance", amounts: []int{5000, 16000}, wantPaid: 21000, wantStatus: model.PaymentStatusPaid},
+		{name: "over payment", amounts: []int{25000}, wantErr: exceptions.ErrPaymentExceedsBalance, wantStatus: model.PaymentStatusUnpaid},
+		{name: "over payment after partial", amounts: []int{20000, 2000}, wantErr: exceptions.ErrPaymentExceedsBalance, wantPaid: 20000, wantStatus: model.PaymentStatusPartiallyPaid},
+		{name: "already paid", amounts: []int{21000, 1000}, wantErrText: "already paid", wantPaid: 21000, wantStatus: model.PaymentStatusPaid},
+		{name: "zero amount", amounts: []int{0}, wantErrText: "greater than zero", wantStatus: model.PaymentStatusUnpaid},
+		{name: "negative amount", amounts: []int{-1000}, wantErrText: "greater than zero", wantStatus: model.PaymentStatusUnpaid},
+		{name: "unknown method", amounts: []int{1000}, method: "debit", wantErrText: "not supported", wantStatus: model.PaymentStatusUnpaid},
+		{name: "cancelled bill", amounts: []int{1000}, void: true, wantErrText: "cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucm := newUseCaseManager(t, newTestConfig(t))
+			seedBill(t, ucm)
+			if tt.void {
+				mustNoErr(t, ucm.BillUseCase().VoidBill("b1", "salah input", "owner"))
+			}
+
+			var err error
+			for i, amount := range tt.amounts {
+				method := model.PaymentMethodCash
+				if tt.method != "" && i == len(tt.amounts)-1 {
+					method = tt.method
+				}
+				_, err = ucm.PaymentUseCase().RegisterNewPayment(model.Payment{BillId: "b1", Amount: amount, Method: method, ReceivedBy: "kasir"})
+				if i < len(tt.amounts)-1 {
+					mustNoErr(t, err)
+				}
+			}
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			case tt.wantErrText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrText, err)
+				}
+			default:
+				mustNoErr(t, err)
+			}
+			if tt.void {
+				return
+			}
+
+			bill, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+			if bill.AmountPaid != tt.wantPaid || bill.BalanceDue != 21000-tt.wantPaid || bill.PaymentStatus != tt.wantStatus {
+				t.Errorf("expected paid %d status %s, got paid %d balance %d status %s", tt.wantPaid, tt.wantStatus, bill.AmountPaid, bill.BalanceDue, bill.PaymentStatus)
+			}
+		})
+	}
+}
@@ -6,31 +6,31 @@ import (
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/model/dto"
 	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type ProductUseCase interface {
-	RegisterNewProduct(payload model.Product) error
+	RegisterNewProduct(payload model.Product, actor string) error
 	FindAllProduct(requesPaging dto.PaginationParam) ([]model.Product, dto.Paging, error)
 	FindByIdProduct(id string) (model.Product, error)
-	UpdateProduct(payload model.Product) error
-	DeleteProduct(id string) error
+	// FindByIdProductIncludeDeleted dipakai untuk detail bill lama yang produknya mungkin sudah di soft delete
+	FindByIdProductIncludeDeleted(id string) (model.Product, error)
+	UpdateProduct(payload model.Product, actor string) (model.Product, error)
+	DeleteProduct(id string, actor string) error
+	RestoreProduct(id string, actor string) error
 }
 
 type productUseCase struct {
-	repo  repository.ProductRepository
-	uomUC UomUseCase
+	repo    repository.ProductRepository
+	uomUC   UomUseCase
+	auditUC AuditUseCase
 }
 
 // RegisterNewProduct implements ProductUseCase.
-func (p *productUseCase) RegisterNewProduct(payload model.Product) error {
-	if payload.Name == "" || payload.Price == 0 || payload.Uom.Id == "" {
-		return fmt.Errorf("name, price and uomID are required fields")
-	}
-
-	// cek uom ada atau tidak
-	uom, err := p.uomUC.FindByIdUom(payload.Uom.Id)
+func (p *productUseCase) RegisterNewProduct(payload model.Product, actor string) error {
+	uom, err := p.validateProduct(payload)
 	if err != nil {
-		return fmt.Errorf("uom with ID %s not found", payload.Uom.Id)
+		return err
 	}
 
 	payload.Uom = uom
@@ -38,6 +38,7 @@ func (p *productUseCase) RegisterNewProduct(payload model.Product) error {
 	if err != nil {
 		return fmt.Errorf("failed to register new product: %v", err)
 	}
+	p.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityProduct, payload.Id, nil, payload)
 	return nil
 }
 
@@ -51,16 +52,87 @@ func (p *productUseCase) FindByIdProduct(id string) (model.Product, error) {
 	return p.repo.Get(id)
 }
 
+// FindByIdProductIncludeDeleted implements ProductUseCase.
+func (p *productUseCase) FindByIdProductIncludeDeleted(id string) (model.Product, error) {
+	return p.repo.GetIncludeDeleted(id)
+}
+
 // UpdateProduct implements ProductUseCase.
-func (p *productUseCase) UpdateProduct(payload model.Product) error {
-	return p.repo.Update(payload)
+func (p *productUseCase) UpdateProduct(payload model.Product, actor string) (model.Product, error) {
+	before, err := p.FindByIdProduct(payload.Id)
+	if err != nil {
+		return model.Product{}, fmt.Errorf("product with ID %s not found", payload.Id)
+	}
+	if before.Version != payload.Version {
+		return model.Product{}, exceptions.ErrVersionConflict
+	}
+	uom, err := p.validateProduct(payload)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	payload.Uom = uom
+	if err := p.repo.Update(payload); err != nil {
+		return model.Product{}, err
+	}
+	updated, err := p.repo.Get(payload.Id)
+	if err != nil {
+		return model.Product{}, fmt.Errorf("failed to get updated product: %v", err)
+	}
+	p.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityProduct, payload.Id, before, updated)
+	return updated, nil
 }
 
 // DeleteProduct implements ProductUseCase.
-func (p *productUseCase) DeleteProduct(id string) error {
-	return p.repo.Delete(id)
+func (p *productUseCase) DeleteProduct(id string, actor string) error {
+	before, err := p.FindByIdProduct(id)
+	if err != nil {
+		return fmt.Errorf("product with ID %s not found", id)
+	}
+	if err := p.repo.Delete(id); err != nil {
+		return err
+	}
+	p.auditUC.Record(actor, model.AuditActionDelete, model.AuditEntityProduct, id, before, nil)
+	return nil
+}
+
+// RestoreProduct implements ProductUseCase.
+func (p *productUseCase) RestoreProduct(id string, actor string) error {
+	product, err := p.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return fmt.Errorf("product with ID %s not found", id)
+	}
+	if product.DeletedAt == nil {
+		return fmt.Errorf("product with ID %s is not deleted", id)
+	}
+
+	err = p.repo.Restore(product.Id)
+	if err != nil {
+		return fmt.Errorf("failed to restore product: %v", err)
+	}
+	after := product
+	after.DeletedAt = nil
+	p.auditUC.Record(actor, model.AuditActionRestore, model.AuditEntityProduct, product.Id, product, after)
+	return nil
+}
+
+// validateProduct dipakai saat create dan update, mengembalikan uom lengkap dari uomID
+func (p *productUseCase) validateProduct(payload model.Product) (model.Uom, error) {
+	if payload.Name == "" || payload.Price == 0 || payload.Uom.Id == "" {
+		return model.Uom{}, fmt.Errorf("name, price and uomID are required fields")
+	}
+	if payload.TurnaroundHours < 0 {
+		return model.Uom{}, fmt.Errorf("turnaround hours can not be negative")
+	}
+
+	// cek uom ada atau tidak
+	uom, err := p.uomUC.FindByIdUom(payload.Uom.Id)
+	if err != nil {
+		return model.Uom{}, fmt.Errorf("uom with ID %s not found", payload.Uom.Id)
+	}
+	return uom, nil
 }
 
-func NewProductUseCase(repo repository.ProductRepository, uomUC UomUseCase) ProductUseCase {
-	return &productUseCase{repo: repo, uomUC: uomUC}
+func NewProductUseCase(repo repository.ProductRepository, uomUC UomUseCase, auditUC AuditUseCase) ProductUseCase {
+	return &productUseCase{repo: repo, uomUC: uomUC, auditUC: auditUC}
 }
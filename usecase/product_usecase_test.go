@@ -0,0 +1,33 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+// version yang dikembalikan update harus sama dengan yang tersimpan, karena controller memakainya untuk ETag
+func TestUpdateProductReturnsStoredVersion(t *testing.T) {
+	ucm := newUseCaseManager(t, newTestConfig(t))
+	seedBill(t, ucm)
+	product, err := ucm.ProductUseCase().FindByIdProduct("p1")
+	mustNoErr(t, err)
+
+	product.Price = 8000
+	updated, err := ucm.ProductUseCase().UpdateProduct(product, "owner")
+	mustNoErr(t, err)
+	stored, err := ucm.ProductUseCase().FindByIdProduct("p1")
+	mustNoErr(t, err)
+	if updated.Version != stored.Version || stored.Version != product.Version+1 {
+		t.Errorf("expected version %d, got updated %d stored %d", product.Version+1, updated.Version, stored.Version)
+	}
+	if updated.Price != 8000 || updated.Uom.Name != "Kg" {
+		t.Errorf("expected the stored product, got %+v", updated)
+	}
+
+	// update dengan version lama ditolak
+	if _, err := ucm.ProductUseCase().UpdateProduct(product, "owner"); !errors.Is(err, exceptions.ErrVersionConflict) {
+		t.Errorf("expected version conflict, got %v", err)
+	}
+}
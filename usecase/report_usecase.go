@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/NursiNursi/laundry-apps/repository"
+)
+
+const (
+	ReportGroupByDay   = "day"
+	ReportGroupByWeek  = "week"
+	ReportGroupByMonth = "month"
+)
+
+type ReportUseCase interface {
+	GetRevenueReport(from time.Time, to time.Time, groupBy string) (dto.RevenueReportDto, error)
+	GetEmployeeReport(from time.Time, to time.Time) (dto.EmployeeReportDto, error)
+}
+
+type reportUseCase struct {
+	repo         repository.ReportRepository
+	employeeRepo repository.EmployeeRepository
+	cfg          config.BusinessConfig
+}
+
+// GetRevenueReport implements ReportUseCase.
+func (r *reportUseCase) GetRevenueReport(from time.Time, to time.Time, groupBy string) (dto.RevenueReportDto, error) {
+	if groupBy == "" {
+		groupBy = ReportGroupByDay
+	}
+	if groupBy != ReportGroupByDay && groupBy != ReportGroupByWeek && groupBy != ReportGroupByMonth {
+		return dto.RevenueReportDto{}, fmt.Errorf("groupBy must be one of day, week or month")
+	}
+
+	from, to, err := r.reportRange(from, to)
+	if err != nil {
+		return dto.RevenueReportDto{}, err
+	}
+
+	revenues, err := r.repo.ListDailyRevenue(from, to)
+	if err != nil {
+		return dto.RevenueReportDto{}, fmt.Errorf("failed to get revenue report: %v", err)
+	}
+	orderCounts, err := r.repo.ListDailyOrderCount(from, to)
+	if err != nil {
+		return dto.RevenueReportDto{}, fmt.Errorf("failed to get revenue report: %v", err)
+	}
+
+	report := dto.RevenueReportDto{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		GroupBy:  groupBy,
+		TimeZone: r.cfg.Location.String(),
+	}
+	periodIndex := make(map[string]int)
+	period := func(date time.Time) *dto.RevenuePeriodDto {
+		key := reportPeriod(date, groupBy)
+		index, ok := periodIndex[key]
+		if !ok {
+			report.Periods = append(report.Periods, dto.RevenuePeriodDto{Period: key})
+			index = len(report.Periods) - 1
+			periodIndex[key] = index
+		}
+		return &report.Periods[index]
+	}
+
+	for _, revenue := range revenues {
+		current := period(revenue.BillDate)
+		current.Revenue += revenue.Revenue
+		current.ByProduct = addRevenueByProduct(current.ByProduct, revenue)
+		current.ByUom = addRevenueByUom(current.ByUom, revenue)
+		report.TotalRevenue += revenue.Revenue
+		report.ByProduct = addRevenueByProduct(report.ByProduct, revenue)
+		report.ByUom = addRevenueByUom(report.ByUom, revenue)
+	}
+	for _, orderCount := range orderCounts {
+		current := period(orderCount.BillDate)
+		current.Orders += orderCount.Orders
+		report.TotalOrders += orderCount.Orders
+	}
+
+	for i := range report.Periods {
+		report.Periods[i].AverageTicket = averageTicket(report.Periods[i].Revenue, report.Periods[i].Orders)
+	}
+	report.AverageTicket = averageTicket(report.TotalRevenue, report.TotalOrders)
+	return report, nil
+}
+
+// GetEmployeeReport implements ReportUseCase.
+func (r *reportUseCase) GetEmployeeReport(from time.Time, to time.Time) (dto.EmployeeReportDto, error) {
+	from, to, err := r.reportRange(from, to)
+	if err != nil {
+		return dto.EmployeeReportDto{}, err
+	}
+
+	employees, err := r.employeeRepo.List()
+	if err != nil {
+		return dto.EmployeeReportDto{}, fmt.Errorf("failed to get employee report: %v", err)
+	}
+	employeeBills, err := r.repo.ListEmployeeBills(from, to)
+	if err != nil {
+		return dto.EmployeeReportDto{}, fmt.Errorf("failed to get employee report: %v", err)
+	}
+
+	report := dto.EmployeeReportDto{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Employees: make([]dto.EmployeeWorkloadDto, 0, len(employees)),
+	}
+	employeeIndex := make(map[string]int)
+	for i, employee := range employees {
+		report.Employees = append(report.Employees, dto.EmployeeWorkloadDto{Employee: employee})
+		employeeIndex[employee.Id] = i
+	}
+
+	turnaround := make(map[string]time.Duration)
+	for _, employeeBill := range employeeBills {
+		index, ok := employeeIndex[employeeBill.EmployeeId]
+		if !ok {
+			continue
+		}
+		workload := &report.Employees[index]
+		workload.BillsHandled++
+		workload.RevenueHandled += employeeBill.Total
+		turnaround[employeeBill.EmployeeId] += employeeBill.FinishDate.Sub(employeeBill.EntryDate)
+		if employeeBill.ReadyAt != nil {
+			workload.FinishedBills++
+			if employeeBill.ReadyAt.After(employeeBill.FinishDate) {
+				workload.LateFinishes++
+			}
+		}
+	}
+	for i, workload := range report.Employees {
+		if workload.BillsHandled > 0 {
+			average := turnaround[workload.Employee.Id].Hours() / float64(workload.BillsHandled)
+			report.Employees[i].AverageTurnaroundHours = math.Round(average*100) / 100
+		}
+	}
+	return report, nil
+}
+
+// reportRange mengisi default periode laporan: dari awal bulan berjalan sampai hari ini (zona waktu toko)
+func (r *reportUseCase) reportRange(from time.Time, to time.Time) (time.Time, time.Time, error) {
+	today := time.Now().In(r.cfg.Location)
+	if to.IsZero() {
+		to = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, r.cfg.Location)
+	}
+	if from.IsZero() {
+		from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, r.cfg.Location)
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date must not be after to date")
+	}
+	return from, to, nil
+}
+
+// reportPeriod mengembalikan label periode: tanggal untuk day, tanggal senin untuk week, YYYY-MM untuk month
+func reportPeriod(date time.Time, groupBy string) string {
+	switch groupBy {
+	case ReportGroupByWeek:
+		offset := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -offset).Format("2006-01-02")
+	case ReportGroupByMonth:
+		return date.Format("2006-01")
+	}
+	return date.Format("2006-01-02")
+}
+
+func addRevenueByProduct(products []dto.RevenueByProductDto, revenue dto.RevenueRowDto) []dto.RevenueByProductDto {
+	for i := range products {
+		if products[i].ProductId == revenue.ProductId {
+			products[i].Qty += revenue.Qty
+			products[i].Revenue += revenue.Revenue
+			return products
+		}
+	}
+	return append(products, dto.RevenueByProductDto{
+		ProductId:   revenue.ProductId,
+		ProductName: revenue.ProductName,
+		UomName:     revenue.UomName,
+		Qty:         revenue.Qty,
+		Revenue:     revenue.Revenue,
+	})
+}
+
+func addRevenueByUom(uoms []dto.RevenueByUomDto, revenue dto.RevenueRowDto) []dto.RevenueByUomDto {
+	for i := range uoms {
+		if uoms[i].UomId == revenue.UomId {
+			uoms[i].Qty += revenue.Qty
+			uoms[i].Revenue += revenue.Revenue
+			return uoms
+		}
+	}
+	return append(uoms, dto.RevenueByUomDto{
+		UomId:   revenue.UomId,
+		UomName: revenue.UomName,
+		Qty:     revenue.Qty,
+		Revenue: revenue.Revenue,
+	})
+}
+
+func averageTicket(revenue int, orders int) int {
+	if orders == 0 {
+		return 0
+	}
+	return revenue / orders
+}
+
+func NewReportUseCase(repo repository.ReportRepository, employeeRepo repository.EmployeeRepository, cfg config.BusinessConfig) ReportUseCase {
+	return &reportUseCase{repo: repo, employeeRepo: employeeRepo, cfg: cfg}
+}
@@ -0,0 +1,214 @@
+package usecase_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+)
+
+// createReportBill menyimpan bill langsung lewat repository supaya tanggalnya bisa ditentukan,
+// bill_date disimpan tanpa jam seperti kolom date di database
+func createReportBill(t *testing.T, repo manager.RepoManager, id string, billDate string, employeeId string, details ...model.BillDetail) {
+	t.Helper()
+	date, err := time.Parse("2006-01-02", billDate)
+	mustNoErr(t, err)
+	for i := range details {
+		details[i].Id = fmt.Sprintf("%s-d%d", id, i+1)
+		details[i].BillId = id
+	}
+	mustNoErr(t, repo.BillRepo().Create(model.Bill{
+		Id:          id,
+		BillDate:    date,
+		EntryDate:   date.Add(9 * time.Hour),
+		FinishDate:  date.Add(57 * time.Hour),
+		EmployeeId:  employeeId,
+		CustomerId:  "c1",
+		Status:      model.BillStatusReceived,
+		BillDetails: details,
+	}))
+}
+
+func TestGetRevenueReport(t *testing.T) {
+	// hasil pengelompokan harus sama di zona waktu positif maupun negatif karena bill_date sudah berupa tanggal toko
+	locations := []*time.Location{time.FixedZone("WIB", 7*60*60), time.FixedZone("HST", -10*60*60)}
+	tests := []struct {
+		groupBy     string
+		from        string
+		to          string
+		wantPeriods []dto.RevenuePeriodDto
+	}{
+		{groupBy: "day", from: "2023-07-31", to: "2023-09-01", wantPeriods: []dto.RevenuePeriodDto{
+			{Period: "2023-07-31", Revenue: 14000, Orders: 1},
+			{Period: "2023-08-06", Revenue: 25000, Orders: 1},
+			{Period: "2023-08-07", Revenue: 7000, Orders: 1},
+			{Period: "2023-08-31", Revenue: 21000, Orders: 1},
+			{Period: "2023-09-01", Revenue: 50000, Orders: 1},
+		}},
+		// minggu dimulai hari senin, bill hari minggu 6 agustus masuk minggu 31 juli
+		{groupBy: "week", from: "2023-07-31", to: "2023-09-01", wantPeriods: []dto.RevenuePeriodDto{
+			{Period: "2023-07-31", Revenue: 39000, Orders: 2},
+			{Period: "2023-08-07", Revenue: 7000, Orders: 1},
+			{Period: "2023-08-28", Revenue: 71000, Orders: 2},
+		}},
+		{groupBy: "month", from: "2023-07-31", to: "2023-09-01", wantPeriods: []dto.RevenuePeriodDto{
+			{Period: "2023-07", Revenue: 14000, Orders: 1},
+			{Period: "2023-08", Revenue: 53000, Orders: 3},
+			{Period: "2023-09", Revenue: 50000, Orders: 1},
+		}},
+		// from dan to inklusif
+		{groupBy: "month", from: "2023-08-01", to: "2023-08-31", wantPeriods: []dto.RevenuePeriodDto{
+			{Period: "2023-08", Revenue: 53000, Orders: 3},
+		}},
+	}
+	for _, location := range locations {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s %s %s-%s", location, tt.groupBy, tt.from, tt.to), func(t *testing.T) {
+				cfg := newTestConfig(t)
+				cfg.Location = location
+				repo, ucm := newManagers(t, cfg)
+				seedBill(t, ucm)
+				createReportBill(t, repo, "r1", "2023-07-31", "e1", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 2})
+				createReportBill(t, repo, "r2", "2023-08-06", "e1", model.BillDetail{ProductId: "p2", ProductPrice: 25000, Qty: 1})
+				createReportBill(t, repo, "r3", "2023-08-07", "e1", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 1})
+				createReportBill(t, repo, "r4", "2023-08-31", "e1", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 3})
+				createReportBill(t, repo, "r5", "2023-09-01", "e1", model.BillDetail{ProductId: "p2", ProductPrice: 25000, Qty: 2})
+				// bill yang di void tidak dihitung
+				createReportBill(t, repo, "r6", "2023-08-07", "e1", model.BillDetail{ProductId: "p2", ProductPrice: 25000, Qty: 4})
+				mustNoErr(t, ucm.BillUseCase().VoidBill("r6", "salah input", "owner"))
+
+				from, err := time.ParseInLocation("2006-01-02", tt.from, location)
+				mustNoErr(t, err)
+				to, err := time.ParseInLocation("2006-01-02", tt.to, location)
+				mustNoErr(t, err)
+				report, err := ucm.ReportUseCase().GetRevenueReport(from, to, tt.groupBy)
+				mustNoErr(t, err)
+
+				if report.From != tt.from || report.To != tt.to || report.TimeZone != location.String() {
+					t.Errorf("unexpected report range %s - %s in %s", report.From, report.To, report.TimeZone)
+				}
+				if len(report.Periods) != len(tt.wantPeriods) {
+					t.Fatalf("expected %d periods, got %+v", len(tt.wantPeriods), report.Periods)
+				}
+				totalRevenue, totalOrders := 0, 0
+				for i, want := range tt.wantPeriods {
+					got := report.Periods[i]
+					if got.Period != want.Period || got.Revenue != want.Revenue || got.Orders != want.Orders || got.AverageTicket != want.Revenue/want.Orders {
+						t.Errorf("period %d: expected %+v, got %+v", i, want, got)
+					}
+					totalRevenue += want.Revenue
+					totalOrders += want.Orders
+				}
+				if report.TotalRevenue != totalRevenue || report.TotalOrders != totalOrders {
+					t.Errorf("expected total %d from %d orders, got %d from %d", totalRevenue, totalOrders, report.TotalRevenue, report.TotalOrders)
+				}
+			})
+		}
+	}
+}
+
+// tanggal bill dan periode default laporan mengikuti APP_TIMEZONE, bukan zona waktu server
+func TestRevenueReportUsesAppTimezone(t *testing.T) {
+	// selisih 26 jam, sepanjang hari tanggal di kedua zona ini berbeda
+	for _, location := range []*time.Location{time.FixedZone("UTC+14", 14*60*60), time.FixedZone("UTC-12", -12*60*60)} {
+		t.Run(location.String(), func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.Location = location
+			ucm := newUseCaseManager(t, cfg)
+			before := time.Now().In(location).Format("2006-01-02")
+			seedBill(t, ucm)
+			report, err := ucm.ReportUseCase().GetRevenueReport(time.Time{}, time.Time{}, "day")
+			mustNoErr(t, err)
+			after := time.Now().In(location).Format("2006-01-02")
+
+			bill, err := ucm.BillUseCase().FindByIdBill("b1")
+			mustNoErr(t, err)
+			billDate := bill.BillDate.Format("2006-01-02")
+			if billDate != before && billDate != after {
+				t.Errorf("bill date should be today in %s (%s), got %s", location, before, billDate)
+			}
+			if report.To != billDate || report.From != billDate[:8]+"01" {
+				t.Errorf("default range should be this month until today in %s, got %s - %s", location, report.From, report.To)
+			}
+			if len(report.Periods) != 1 || report.Periods[0].Period != billDate || report.TotalRevenue != 21000 {
+				t.Errorf("expected today's bill in the report, got %+v", report.Periods)
+			}
+		})
+	}
+}
+
+func TestGetRevenueReportRejectsInvalidRequest(t *testing.T) {
+	ucm := newUseCaseManager(t, newTestConfig(t))
+	from := time.Date(2023, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := ucm.ReportUseCase().GetRevenueReport(from, to, "day"); err == nil {
+		t.Error("from after to should be rejected")
+	}
+	if _, err := ucm.ReportUseCase().GetRevenueReport(to, from, "year"); err == nil {
+		t.Error("unknown groupBy should be rejected")
+	}
+}
+
+// moveBillStatus mencatat riwayat status lewat repository supaya waktu perubahan bisa ditentukan
+func moveBillStatus(t *testing.T, repo manager.RepoManager, billId string, changedAt time.Time, from string, path ...string) {
+	t.Helper()
+	for i, to := range path {
+		mustNoErr(t, repo.BillRepo().UpdateStatus(model.BillStatusHistory{
+			Id:         fmt.Sprintf("%s-h%d", billId, i+1),
+			BillId:     billId,
+			FromStatus: from,
+			ToStatus:   to,
+			ChangedBy:  "operator",
+			ChangedAt:  changedAt.Add(time.Duration(i-len(path)+1) * time.Minute),
+		}))
+		from = to
+	}
+}
+
+func TestGetEmployeeReport(t *testing.T) {
+	cfg := newTestConfig(t)
+	repo, ucm := newManagers(t, cfg)
+	seedBill(t, ucm)
+	mustNoErr(t, ucm.EmployeeUseCase().RegisterNewEmployee(model.Employee{Id: "e2", Name: "Citra", PhoneNumber: "0899"}, "system"))
+	mustNoErr(t, ucm.EmployeeUseCase().RegisterNewEmployee(model.Employee{Id: "e3", Name: "Dodi", PhoneNumber: "0898"}, "system"))
+
+	// semua bill masuk 7 agustus 09:00 dengan estimasi selesai 48 jam kemudian
+	finish := time.Date(2023, 8, 9, 9, 0, 0, 0, time.UTC)
+	toReady := []string{model.BillStatusWashing, model.BillStatusDrying, model.BillStatusReady}
+	createReportBill(t, repo, "k1", "2023-08-07", "e1", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 1})
+	moveBillStatus(t, repo, "k1", finish.Add(-time.Hour), model.BillStatusReceived, toReady...)
+	// diambil customer jauh setelah estimasi tidak dihitung terlambat, yang dipakai adalah ready_at
+	moveBillStatus(t, repo, "k1", finish.Add(48*time.Hour), model.BillStatusReady, model.BillStatusPickedUp)
+	createReportBill(t, repo, "k2", "2023-08-07", "e1", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 2})
+	moveBillStatus(t, repo, "k2", finish.Add(2*time.Hour), model.BillStatusReceived, toReady...)
+	createReportBill(t, repo, "k3", "2023-08-07", "e1", model.BillDetail{ProductId: "p2", ProductPrice: 25000, Qty: 1})
+	moveBillStatus(t, repo, "k3", finish.Add(5*time.Hour), model.BillStatusReceived, model.BillStatusWashing)
+	createReportBill(t, repo, "k4", "2023-08-07", "e2", model.BillDetail{ProductId: "p1", ProductPrice: 7000, Qty: 3})
+	moveBillStatus(t, repo, "k4", finish, model.BillStatusReceived, toReady...)
+	createReportBill(t, repo, "k5", "2023-08-07", "e2", model.BillDetail{ProductId: "p2", ProductPrice: 25000, Qty: 4})
+	mustNoErr(t, ucm.BillUseCase().VoidBill("k5", "salah input", "owner"))
+
+	from := time.Date(2023, 8, 1, 0, 0, 0, 0, cfg.Location)
+	to := time.Date(2023, 8, 31, 0, 0, 0, 0, cfg.Location)
+	report, err := ucm.ReportUseCase().GetEmployeeReport(from, to)
+	mustNoErr(t, err)
+
+	want := map[string]dto.EmployeeWorkloadDto{
+		"e1": {BillsHandled: 3, RevenueHandled: 46000, AverageTurnaroundHours: 48, FinishedBills: 2, LateFinishes: 1},
+		"e2": {BillsHandled: 1, RevenueHandled: 21000, AverageTurnaroundHours: 48, FinishedBills: 1, LateFinishes: 0},
+		"e3": {},
+	}
+	if len(report.Employees) != len(want) {
+		t.Fatalf("expected %d employees, got %+v", len(want), report.Employees)
+	}
+	for _, got := range report.Employees {
+		expected := want[got.Employee.Id]
+		expected.Employee = got.Employee
+		if got != expected {
+			t.Errorf("employee %s: expected %+v, got %+v", got.Employee.Id, expected, got)
+		}
+	}
+}
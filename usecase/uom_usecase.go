@@ -5,22 +5,26 @@ import (
 
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
 )
 
 type UomUseCase interface {
-	RegisterNewUom(payload model.Uom) error
-	FindAllUom() ([]model.Uom, error)
+	RegisterNewUom(payload model.Uom, actor string) error
+	FindAllUom(includeDeleted bool) ([]model.Uom, error)
 	FindByIdUom(id string) (model.Uom, error)
-	UpdateUom(payload model.Uom) error
-	DeleteUom(id string) error
+	FindByIdUomIncludeDeleted(id string) (model.Uom, error)
+	UpdateUom(payload model.Uom, actor string) (model.Uom, error)
+	DeleteUom(id string, actor string) error
+	RestoreUom(id string, actor string) error
 }
 
 type uomUseCase struct {
-	repo repository.UomRepository
+	repo    repository.UomRepository
+	auditUC AuditUseCase
 }
 
 // RegisterNewUom implements UomUseCase.
-func (u *uomUseCase) RegisterNewUom(payload model.Uom) error {
+func (u *uomUseCase) RegisterNewUom(payload model.Uom, actor string) error {
 	if payload.Name == "" {
 		return fmt.Errorf("name required fields")
 	}
@@ -34,10 +38,14 @@ func (u *uomUseCase) RegisterNewUom(payload model.Uom) error {
 	if err != nil {
 		return fmt.Errorf("failed to create new uom: %v", err)
 	}
+	u.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityUom, payload.Id, nil, payload)
 	return nil
 }
 
-func (u *uomUseCase) FindAllUom() ([]model.Uom, error) {
+func (u *uomUseCase) FindAllUom(includeDeleted bool) ([]model.Uom, error) {
+	if includeDeleted {
+		return u.repo.ListIncludeDeleted()
+	}
 	return u.repo.List()
 }
 
@@ -45,7 +53,11 @@ func (u *uomUseCase) FindByIdUom(id string) (model.Uom, error) {
 	return u.repo.Get(id)
 }
 
-func (u *uomUseCase) DeleteUom(id string) error {
+func (u *uomUseCase) FindByIdUomIncludeDeleted(id string) (model.Uom, error) {
+	return u.repo.GetIncludeDeleted(id)
+}
+
+func (u *uomUseCase) DeleteUom(id string, actor string) error {
 	uom, err := u.FindByIdUom(id)
 	if err != nil {
 		return fmt.Errorf("data with ID %s not found", id)
@@ -55,27 +67,63 @@ func (u *uomUseCase) DeleteUom(id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete uom: %v", err)
 	}
+	u.auditUC.Record(actor, model.AuditActionDelete, model.AuditEntityUom, uom.Id, uom, nil)
 	return nil
 }
 
-func (u *uomUseCase) UpdateUom(payload model.Uom) error {
+func (u *uomUseCase) UpdateUom(payload model.Uom, actor string) (model.Uom, error) {
 	if payload.Name == "" {
-		return fmt.Errorf("name is required field")
+		return model.Uom{}, fmt.Errorf("name is required field")
+	}
+	before, err := u.FindByIdUom(payload.Id)
+	if err != nil {
+		return model.Uom{}, fmt.Errorf("data with ID %s not found", payload.Id)
+	}
+	if before.Version != payload.Version {
+		return model.Uom{}, exceptions.ErrVersionConflict
 	}
 
 	isExistUom, _ := u.repo.GetByName(payload.Name)
 	if isExistUom.Name == payload.Name && isExistUom.Id != payload.Id {
-		return fmt.Errorf("uom with name %s exists", payload.Name)
+		return model.Uom{}, fmt.Errorf("uom with name %s exists", payload.Name)
 	}
 
-	err := u.repo.Update(payload)
+	err = u.repo.Update(payload)
+	if err != nil {
+		return model.Uom{}, fmt.Errorf("failed to update uom: %w", err)
+	}
+	updated, err := u.repo.Get(payload.Id)
 	if err != nil {
-		return fmt.Errorf("failed to update uom: %v", err)
+		return model.Uom{}, fmt.Errorf("failed to get updated uom: %v", err)
+	}
+	u.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityUom, payload.Id, before, updated)
+	return updated, nil
+}
+
+func (u *uomUseCase) RestoreUom(id string, actor string) error {
+	uom, err := u.repo.GetIncludeDeleted(id)
+	if err != nil {
+		return fmt.Errorf("uom with ID %s not found", id)
+	}
+	if uom.DeletedAt == nil {
+		return fmt.Errorf("uom with ID %s is not deleted", id)
+	}
+	// nama uom tidak unique di database, jadi dicek lagi kalau nama yang sama sudah dibuat ulang selama uom ini terhapus
+	isExistUom, _ := u.repo.GetByName(uom.Name)
+	if isExistUom.Name == uom.Name {
+		return fmt.Errorf("uom with name %s exists", uom.Name)
 	}
 
+	err = u.repo.Restore(uom.Id)
+	if err != nil {
+		return fmt.Errorf("failed to restore uom: %v", err)
+	}
+	after := uom
+	after.DeletedAt = nil
+	u.auditUC.Record(actor, model.AuditActionRestore, model.AuditEntityUom, uom.Id, uom, after)
 	return nil
 }
 
-func NewUomUseCase(repo repository.UomRepository) UomUseCase {
-	return &uomUseCase{repo: repo}
+func NewUomUseCase(repo repository.UomRepository, auditUC AuditUseCase) UomUseCase {
+	return &uomUseCase{repo: repo, auditUC: auditUC}
 }
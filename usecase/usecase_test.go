@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/manager"
+)
+
+// isi .env untuk test, security.CreateAccessToken dan common.GetPaginationParams membaca .env dari working directory
+const testEnv = `DB_DRIVER=memory
+API_HOST=localhost
+API_PORT=8080
+FILE_PATH=laundry.log
+APP_TOKEN_NAME=laundry-test
+APP_TOKEN_KEY=laundry-test-key
+APP_TOKEN_EXPIRE=15
+DEFAULT_ROWS_PER_PAGE=10
+`
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "laundry-apps-test")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(testEnv), 0o600); err != nil {
+		log.Fatalln(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		log.Fatalln(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newTestConfig membaca .env test, test boleh mengubah nilainya sebelum memanggil newUseCaseManager
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// newUseCaseManager memakai repository memory, setiap test mendapat store yang kosong
+func newUseCaseManager(t *testing.T, cfg *config.Config) manager.UseCaseManager {
+	t.Helper()
+	_, ucm := newManagers(t, cfg)
+	return ucm
+}
+
+// newManagers dipakai test yang perlu mengisi data langsung lewat repository, misalnya bill dengan tanggal tertentu
+func newManagers(t *testing.T, cfg *config.Config) (manager.RepoManager, manager.UseCaseManager) {
+	t.Helper()
+	infra, err := manager.NewInfraManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := manager.NewRepoManager(infra)
+	return repo, manager.NewUseCaseManager(repo, cfg)
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
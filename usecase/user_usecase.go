@@ -2,21 +2,36 @@ package usecase
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/NursiNursi/laundry-apps/config"
 	"github.com/NursiNursi/laundry-apps/model"
 	"github.com/NursiNursi/laundry-apps/repository"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+	"github.com/NursiNursi/laundry-apps/utils/security"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUseCase interface {
-	RegisterNewUser(paylaod model.UserCredential) error
+	RegisterNewUser(paylaod model.UserCredential, actor string, actorRole string) error
 	FindAllUser() ([]model.UserCredential, error)
+	FindByIdUser(id string) (model.UserCredential, error)
 	FindByUsername(username string) (model.UserCredential, error)
 	FindByUsernamePassword(username string, password string) (model.UserCredential, error)
+	ChangePassword(username string, oldPassword string, newPassword string) error
+	ResetPassword(id string, newPassword string, actor string, actorRole string) error
+	UpdateActiveUser(id string, isActive bool, actor string, actorRole string) error
+	UpdateEmployeeUser(id string, employeeId string, actor string, actorRole string) error
+	DeleteUser(id string, actor string, actorRole string) error
 }
 
 type userUseCase struct {
-	repo repository.UserRepository
+	repo        repository.UserRepository
+	sessionRepo repository.SessionRepository
+	cache       *security.SessionCache
+	policy      config.PasswordConfig
+	empUseCase  EmployeeUseCase
+	auditUC     AuditUseCase
 }
 
 // FindAllUser implements UserUseCase.
@@ -24,6 +39,11 @@ func (u *userUseCase) FindAllUser() ([]model.UserCredential, error) {
 	return u.repo.List()
 }
 
+// FindByIdUser implements UserUseCase.
+func (u *userUseCase) FindByIdUser(id string) (model.UserCredential, error) {
+	return u.repo.Get(id)
+}
+
 // FindByUsername implements UserUseCase.
 func (u *userUseCase) FindByUsername(username string) (model.UserCredential, error) {
 	return u.repo.GetUsername(username)
@@ -35,7 +55,27 @@ func (u *userUseCase) FindByUsernamePassword(username string, password string) (
 }
 
 // RegisterNewUser implements UserUseCase.
-func (u *userUseCase) RegisterNewUser(paylaod model.UserCredential) error {
+func (u *userUseCase) RegisterNewUser(paylaod model.UserCredential, actor string, actorRole string) error {
+	if paylaod.Username == "" || paylaod.Password == "" {
+		return fmt.Errorf("username and password are required fields")
+	}
+	if paylaod.Role == "" {
+		paylaod.Role = model.RoleCashier
+	}
+	if !security.IsValidRole(paylaod.Role) {
+		return fmt.Errorf("role %s is not valid, use owner, admin, cashier or operator", paylaod.Role)
+	}
+	if err := checkRoleAssignment(paylaod.Role, actorRole); err != nil {
+		return err
+	}
+	if err := security.ValidatePassword(paylaod.Password, u.policy); err != nil {
+		return err
+	}
+	if paylaod.EmployeeId != "" {
+		if _, err := u.empUseCase.FindByIdEmployee(paylaod.EmployeeId); err != nil {
+			return fmt.Errorf("employee with ID %s not found", paylaod.EmployeeId)
+		}
+	}
 	// bytes => sjiadbafiaf7asf8af8as8fasnfajfcnas!dcscsjc
 	bytes, _ := bcrypt.GenerateFromPassword([]byte(paylaod.Password), bcrypt.DefaultCost)
 	paylaod.Password = string(bytes)
@@ -43,9 +83,188 @@ func (u *userUseCase) RegisterNewUser(paylaod model.UserCredential) error {
 	if err != nil {
 		return fmt.Errorf("failed to create user %v", err)
 	}
+	paylaod.IsActive = true
+	u.auditUC.Record(actor, model.AuditActionCreate, model.AuditEntityUser, paylaod.Id, nil, auditUser(paylaod))
+	return nil
+}
+
+// ChangePassword implements UserUseCase.
+func (u *userUseCase) ChangePassword(username string, oldPassword string, newPassword string) error {
+	if oldPassword == "" || newPassword == "" {
+		return fmt.Errorf("old password and new password are required fields")
+	}
+	user, err := u.repo.GetUsernamePassword(username, oldPassword)
+	if err != nil {
+		return fmt.Errorf("old password is incorrect")
+	}
+	if oldPassword == newPassword {
+		return fmt.Errorf("new password must be different from the old password")
+	}
+	if err := u.updatePassword(user.Id, newPassword); err != nil {
+		return err
+	}
+	u.auditUC.Record(username, model.AuditActionChangePassword, model.AuditEntityUser, user.Id, nil, nil)
+	return nil
+}
+
+// ResetPassword implements UserUseCase.
+func (u *userUseCase) ResetPassword(id string, newPassword string, actor string, actorRole string) error {
+	if newPassword == "" {
+		return fmt.Errorf("new password is required field")
+	}
+	user, err := u.FindByIdUser(id)
+	if err != nil {
+		return fmt.Errorf("user with ID %s not found", id)
+	}
+	if err := checkManageUser(user, actorRole); err != nil {
+		return err
+	}
+	if err := u.updatePassword(user.Id, newPassword); err != nil {
+		return err
+	}
+	u.auditUC.Record(actor, model.AuditActionResetPassword, model.AuditEntityUser, user.Id, nil, nil)
+	return nil
+}
+
+// UpdateActiveUser implements UserUseCase.
+func (u *userUseCase) UpdateActiveUser(id string, isActive bool, actor string, actorRole string) error {
+	user, err := u.FindByIdUser(id)
+	if err != nil {
+		return fmt.Errorf("user with ID %s not found", id)
+	}
+	if err := checkManageUser(user, actorRole); err != nil {
+		return err
+	}
+	if user.Username == actor && !isActive {
+		return fmt.Errorf("can not deactivate your own account")
+	}
+	if !isActive {
+		if err := u.checkLastOwner(user); err != nil {
+			return err
+		}
+	}
+	if err := u.repo.UpdateActive(user.Id, isActive); err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	after := user
+	after.IsActive = isActive
+	u.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityUser, user.Id, auditUser(user), auditUser(after))
+	if !isActive {
+		return u.revokeSessions(user.Id)
+	}
+	return nil
+}
+
+// UpdateEmployeeUser implements UserUseCase.
+func (u *userUseCase) UpdateEmployeeUser(id string, employeeId string, actor string, actorRole string) error {
+	user, err := u.FindByIdUser(id)
+	if err != nil {
+		return fmt.Errorf("user with ID %s not found", id)
+	}
+	if err := checkManageUser(user, actorRole); err != nil {
+		return err
+	}
+	// employeeId kosong berarti melepas user dari karyawan
+	if employeeId != "" {
+		if _, err := u.empUseCase.FindByIdEmployee(employeeId); err != nil {
+			return fmt.Errorf("employee with ID %s not found", employeeId)
+		}
+	}
+	if err := u.repo.UpdateEmployee(user.Id, employeeId); err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	after := user
+	after.EmployeeId = employeeId
+	u.auditUC.Record(actor, model.AuditActionUpdate, model.AuditEntityUser, user.Id, auditUser(user), auditUser(after))
+	// employeeId ada di claims token, login ulang supaya token memakai karyawan yang baru
+	return u.revokeSessions(user.Id)
+}
+
+// DeleteUser implements UserUseCase.
+func (u *userUseCase) DeleteUser(id string, actor string, actorRole string) error {
+	user, err := u.FindByIdUser(id)
+	if err != nil {
+		return fmt.Errorf("user with ID %s not found", id)
+	}
+	if err := checkManageUser(user, actorRole); err != nil {
+		return err
+	}
+	if user.Username == actor {
+		return fmt.Errorf("can not delete your own account")
+	}
+	if err := u.checkLastOwner(user); err != nil {
+		return err
+	}
+	if err := u.repo.Delete(user.Id); err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+	u.cache.RevokeUser(user.Id)
+	u.auditUC.Record(actor, model.AuditActionDelete, model.AuditEntityUser, user.Id, auditUser(user), nil)
+	return nil
+}
+
+func (u *userUseCase) updatePassword(id string, password string) error {
+	if err := security.ValidatePassword(password, u.policy); err != nil {
+		return err
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+	if err := u.repo.UpdatePassword(id, string(bytes)); err != nil {
+		return fmt.Errorf("failed to update password: %v", err)
+	}
+	// token yang sudah beredar tidak boleh dipakai lagi setelah password berubah
+	return u.revokeSessions(id)
+}
+
+func (u *userUseCase) revokeSessions(userId string) error {
+	if err := u.sessionRepo.RevokeByUser(userId, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %v", err)
+	}
+	u.cache.RevokeUser(userId)
 	return nil
 }
 
-func NewUserUseCase(repo repository.UserRepository) UserUseCase {
-	return &userUseCase{repo: repo}
+// checkLastOwner menolak menonaktifkan atau menghapus owner aktif terakhir, supaya selalu ada yang bisa melihat laporan dan audit
+func (u *userUseCase) checkLastOwner(user model.UserCredential) error {
+	if user.Role != model.RoleOwner || !user.IsActive {
+		return nil
+	}
+	users, err := u.repo.List()
+	if err != nil {
+		return fmt.Errorf("failed to check owner accounts: %v", err)
+	}
+	for _, other := range users {
+		if other.Id != user.Id && other.Role == model.RoleOwner && other.IsActive {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: can not deactivate or delete the last active owner", exceptions.ErrForbidden)
+}
+
+// akun owner hanya boleh diubah oleh owner, kalau tidak admin bisa reset password owner lalu login sebagai owner
+func checkManageUser(user model.UserCredential, actorRole string) error {
+	if user.Role == model.RoleOwner && actorRole != model.RoleOwner {
+		return fmt.Errorf("%w: only owner can manage owner account", exceptions.ErrForbidden)
+	}
+	return nil
+}
+
+// role owner dan admin hanya boleh diberikan oleh owner, supaya admin tidak bisa menaikkan hak aksesnya sendiri
+func checkRoleAssignment(role string, actorRole string) error {
+	if (role == model.RoleOwner || role == model.RoleAdmin) && actorRole != model.RoleOwner {
+		return fmt.Errorf("%w: only owner can assign role %s", exceptions.ErrForbidden, role)
+	}
+	return nil
+}
+
+// hash password tidak ikut dicatat di audit
+func auditUser(user model.UserCredential) model.UserCredential {
+	user.Password = ""
+	return user
+}
+
+func NewUserUseCase(repo repository.UserRepository, sessionRepo repository.SessionRepository, cache *security.SessionCache, policy config.PasswordConfig, empUseCase EmployeeUseCase, auditUC AuditUseCase) UserUseCase {
+	return &userUseCase{repo: repo, sessionRepo: sessionRepo, cache: cache, policy: policy, empUseCase: empUseCase, auditUC: auditUC}
 }
@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/manager"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/utils/exceptions"
+)
+
+// admin tidak boleh mengubah akun owner lewat method manapun, owner tetap boleh
+func TestManageOwnerAccountRequiresOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		manage func(ucm manager.UseCaseManager, actorRole string) error
+	}{
+		{name: "reset password", manage: func(ucm manager.UseCaseManager, actorRole string) error {
+			return ucm.UserUseCase().ResetPassword("us-owner", "BaruRahasia123", "pengelola", actorRole)
+		}},
+		{name: "update employee", manage: func(ucm manager.UseCaseManager, actorRole string) error {
+			return ucm.UserUseCase().UpdateEmployeeUser("us-owner", "e1", "pengelola", actorRole)
+		}},
+		{name: "unlink employee", manage: func(ucm manager.UseCaseManager, actorRole string) error {
+			return ucm.UserUseCase().UpdateEmployeeUser("us-owner", "", "pengelola", actorRole)
+		}},
+		{name: "deactivate", manage: func(ucm manager.UseCaseManager, actorRole string) error {
+			return ucm.UserUseCase().UpdateActiveUser("us-owner", false, "pengelola", actorRole)
+		}},
+		{name: "delete", manage: func(ucm manager.UseCaseManager, actorRole string) error {
+			return ucm.UserUseCase().DeleteUser("us-owner", "pengelola", actorRole)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucm := newUseCaseManager(t, newTestConfig(t))
+			mustNoErr(t, ucm.EmployeeUseCase().RegisterNewEmployee(model.Employee{Id: "e1", Name: "Budi", PhoneNumber: "0811", Address: "Jl. Mawar"}, "system"))
+			mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-owner", Username: "owner", Password: "Rahasia123", Role: model.RoleOwner}, "system", model.RoleOwner))
+			// owner kedua supaya owner pertama boleh dinonaktifkan atau dihapus
+			mustNoErr(t, ucm.UserUseCase().RegisterNewUser(model.UserCredential{Id: "us-owner2", Username: "owner2", Password: "Rahasia123", Role: model.RoleOwner}, "system", model.RoleOwner))
+
+			if err := tt.manage(ucm, model.RoleAdmin); !errors.Is(err, exceptions.ErrForbidden) {
+				t.Errorf("admin should be forbidden, got %v", err)
+			}
+			if err := tt.manage(ucm, model.RoleOwner); err != nil {
+				t.Errorf("owner should be allowed, got %v", err)
+			}
+		})
+	}
+}
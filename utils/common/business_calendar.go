@@ -0,0 +1,39 @@
+package common
+
+import "time"
+
+// EstimateFinishDate menambahkan durasi pengerjaan ke start, jam pada hari tutup dan hari libur tidak dihitung
+func EstimateFinishDate(start time.Time, turnaround time.Duration, closedDays []time.Weekday, holidays []time.Time) time.Time {
+	finish := start
+	remaining := turnaround
+	// batas satu tahun supaya tidak looping terus kalau kalender salah konfigurasi
+	limit := start.AddDate(1, 0, 0)
+	for finish.Before(limit) {
+		nextDay := time.Date(finish.Year(), finish.Month(), finish.Day()+1, 0, 0, 0, 0, finish.Location())
+		if isClosedDay(finish, closedDays, holidays) {
+			finish = nextDay
+			continue
+		}
+		if available := nextDay.Sub(finish); remaining > available {
+			remaining -= available
+			finish = nextDay
+			continue
+		}
+		return finish.Add(remaining)
+	}
+	return finish
+}
+
+func isClosedDay(date time.Time, closedDays []time.Weekday, holidays []time.Time) bool {
+	for _, day := range closedDays {
+		if date.Weekday() == day {
+			return true
+		}
+	}
+	for _, holiday := range holidays {
+		if holiday.Year() == date.Year() && holiday.YearDay() == date.YearDay() {
+			return true
+		}
+	}
+	return false
+}
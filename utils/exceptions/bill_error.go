@@ -0,0 +1,14 @@
+package exceptions
+
+import "errors"
+
+// error bill yang dipetakan controller ke status http: not found 404, request tidak valid 400, konflik status 409
+var (
+	ErrBillNotFound       = errors.New("bill not found")
+	ErrInvalidBillRequest = errors.New("invalid bill request")
+	// transisi status yang tidak diperbolehkan, atau status bill sudah diubah request lain
+	ErrBillStatusConflict = errors.New("bill status conflict")
+)
+
+// ErrBillTotalBelowAmountPaid dikembalikan repository ketika total bill setelah di amend lebih kecil dari yang sudah dibayar
+var ErrBillTotalBelowAmountPaid = errors.New("amended total is less than amount paid")
@@ -0,0 +1,6 @@
+package exceptions
+
+import "errors"
+
+// ErrForbidden dikembalikan usecase ketika user yang login tidak boleh mengubah data tersebut, controller membalas 403
+var ErrForbidden = errors.New("forbidden")
@@ -0,0 +1,6 @@
+package exceptions
+
+import "errors"
+
+// ErrIdempotencyKeyReused dikembalikan ketika Idempotency-Key yang sama dikirim dengan request yang berbeda
+var ErrIdempotencyKeyReused = errors.New("idempotency key has already been used for a different request")
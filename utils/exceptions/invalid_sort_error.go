@@ -0,0 +1,6 @@
+package exceptions
+
+import "errors"
+
+// ErrInvalidSort dikembalikan repository ketika sortBy atau sortOrder tidak ada di whitelist, controller membalas 400
+var ErrInvalidSort = errors.New("invalid sort")
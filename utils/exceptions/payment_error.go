@@ -0,0 +1,6 @@
+package exceptions
+
+import "errors"
+
+// ErrPaymentExceedsBalance dikembalikan repository ketika nominal pembayaran melebihi sisa tagihan saat disimpan
+var ErrPaymentExceedsBalance = errors.New("amount exceeds balance due")
@@ -0,0 +1,24 @@
+package exceptions
+
+import (
+	"fmt"
+	"time"
+)
+
+// TooManyRequestsError dipakai controller untuk membalas 429 dengan header Retry-After
+type TooManyRequestsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds dibulatkan ke atas supaya client tidak mencoba terlalu cepat
+func (e *TooManyRequestsError) RetryAfterSeconds() int {
+	seconds := int((e.RetryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
@@ -0,0 +1,6 @@
+package exceptions
+
+import "errors"
+
+// ErrVersionConflict dikembalikan repository ketika baris sudah diubah orang lain (version tidak cocok)
+var ErrVersionConflict = errors.New("data has been modified by another request, reload and try again")
@@ -0,0 +1,192 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+)
+
+// perintah dasar ESC/POS
+var (
+	escInit        = []byte{0x1B, 0x40}
+	escAlignLeft   = []byte{0x1B, 0x61, 0x00}
+	escAlignCenter = []byte{0x1B, 0x61, 0x01}
+	escBoldOn      = []byte{0x1B, 0x45, 0x01}
+	escBoldOff     = []byte{0x1B, 0x45, 0x00}
+	escDoubleSize  = []byte{0x1D, 0x21, 0x11}
+	escNormalSize  = []byte{0x1D, 0x21, 0x00}
+	escFeedAndCut  = []byte{0x1B, 0x64, 0x04, 0x1D, 0x56, 0x42, 0x00}
+)
+
+// jumlah karakter font A dan lebar area cetak (dot) per ukuran kertas
+var paperSpecs = map[int]struct {
+	columns int
+	dots    int
+}{
+	58: {columns: 32, dots: 384},
+	80: {columns: 48, dots: 576},
+}
+
+// GenerateEscPos membuat nota bill dalam bentuk byte stream ESC/POS untuk printer thermal 58mm / 80mm
+func GenerateEscPos(bill dto.BillResponseDto, shop config.ShopConfig, paperWidth int) ([]byte, error) {
+	spec, ok := paperSpecs[paperWidth]
+	if !ok {
+		return nil, fmt.Errorf("paper width %dmm is not supported", paperWidth)
+	}
+	width := spec.columns
+	var buf bytes.Buffer
+	line := func(text string) {
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+	}
+	separator := strings.Repeat("-", width)
+
+	buf.Write(escInit)
+
+	// header toko
+	buf.Write(escAlignCenter)
+	buf.Write(escBoldOn)
+	buf.Write(escDoubleSize)
+	for _, text := range wrapText(toASCII(shop.ShopName), width/2) {
+		line(text)
+	}
+	buf.Write(escNormalSize)
+	buf.Write(escBoldOff)
+	for _, text := range []string{shop.ShopAddress, shop.ShopPhoneNumber} {
+		for _, wrapped := range wrapText(toASCII(text), width) {
+			line(wrapped)
+		}
+	}
+
+	// informasi bill
+	buf.Write(escAlignLeft)
+	line(separator)
+	info := [][2]string{
+		{"Bill", bill.Id},
+		{"Date", bill.EntryDate.Format(dateLayout)},
+		{"Finish", bill.FinishDate.Format(dateLayout)},
+		{"Customer", bill.Customer.Name},
+		{"Phone", bill.Customer.PhoneNumber},
+		{"Cashier", bill.Employee.Name},
+	}
+	for _, item := range info {
+		label := fmt.Sprintf("%-9s: ", item[0])
+		for i, text := range wrapText(toASCII(item[1]), width-len(label)) {
+			if i > 0 {
+				label = strings.Repeat(" ", len(label))
+			}
+			line(label + text)
+		}
+	}
+	line(separator)
+
+	// detail item, nama produk di wrap lalu qty x harga dan subtotal rata kanan
+	for _, detail := range bill.BillDetails {
+		for _, text := range wrapText(toASCII(detail.Product.Name), width) {
+			line(text)
+		}
+		qty := fmt.Sprintf("  %d %s x %s", detail.Qty, toASCII(detail.Product.Uom.Name), FormatRupiah(detail.ProductPrice))
+		line(alignRight(qty, FormatRupiah(detail.ProductPrice*detail.Qty), width))
+	}
+	line(separator)
+
+	buf.Write(escBoldOn)
+	line(alignRight("TOTAL", FormatRupiah(bill.TotalBill), width))
+	buf.Write(escBoldOff)
+	line(alignRight("Paid", FormatRupiah(bill.AmountPaid), width))
+	line(alignRight("Balance Due", FormatRupiah(bill.BalanceDue), width))
+	line("")
+
+	// barcode ID bill
+	buf.Write(escAlignCenter)
+	writeBarcode(&buf, bill.Id, spec.dots)
+
+	if shop.ReceiptFooter != "" {
+		line("")
+		for _, text := range wrapText(toASCII(shop.ReceiptFooter), width) {
+			line(text)
+		}
+	}
+	buf.Write(escFeedAndCut)
+	return buf.Bytes(), nil
+}
+
+// writeBarcode mencetak CODE128, kalau tidak muat di lebar kertas diganti QR code
+func writeBarcode(buf *bytes.Buffer, data string, dots int) {
+	data = toASCII(data)
+	// CODE128 code set B: start + data + checksum @11 modul, stop 13 modul
+	if modules := 11*(len(data)+2) + 13; modules <= dots && len(data)+2 <= 255 {
+		buf.Write([]byte{0x1D, 0x48, 0x02}) // HRI di bawah barcode
+		buf.Write([]byte{0x1D, 0x68, 0x50}) // tinggi 80 dot
+		buf.Write([]byte{0x1D, 0x77, 0x01}) // lebar modul 1 dot
+		buf.Write([]byte{0x1D, 0x6B, 0x49, byte(len(data) + 2), '{', 'B'})
+		buf.WriteString(data)
+		buf.WriteByte('\n')
+		return
+	}
+
+	size := len(data) + 3
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00}) // model 2
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x06})       // ukuran modul
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31})       // error correction M
+	buf.Write([]byte{0x1D, 0x28, 0x6B, byte(size % 256), byte(size / 256), 0x31, 0x50, 0x30})
+	buf.WriteString(data)
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30}) // cetak
+	buf.WriteString(data)
+	buf.WriteByte('\n')
+}
+
+// wrapText memecah teks per kata sesuai lebar kolom, kata yang terlalu panjang dipotong paksa
+func wrapText(text string, width int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for len(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func alignRight(left string, right string, width int) string {
+	space := width - len(left) - len(right)
+	if space < 1 {
+		return left + "\n" + strings.Repeat(" ", width-len(right)) + right
+	}
+	return left + strings.Repeat(" ", space) + right
+}
+
+// printer thermal umumnya hanya aman untuk karakter ASCII
+func toASCII(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= 0x20 && r < 0x7F {
+			b.WriteRune(r)
+		} else if r == '\t' || r == '\n' {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package receipt_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/utils/receipt"
+)
+
+// perintah ESC/POS yang tidak tercetak sebagai teks: init, align, bold, ukuran font, HRI, tinggi dan lebar barcode, feed dan cut
+var escPosCommand = regexp.MustCompile(`\x1B@|\x1Ba[\x00-\x02]|\x1BE[\x00\x01]|\x1D![\x00-\xFF]|\x1D[Hhw][\x00-\xFF]|\x1Bd[\x00-\xFF]|\x1DVB[\x00-\xFF]`)
+
+var (
+	code128Command = []byte{0x1D, 0x6B, 0x49}
+	qrCommand      = []byte{0x1D, 0x28, 0x6B}
+)
+
+func TestGenerateEscPos(t *testing.T) {
+	tests := []struct {
+		name        string
+		paperWidth  int
+		billId      string
+		wantColumns int
+		wantQR      bool
+	}{
+		// uuid 36 karakter butuh 11*(36+2)+13 = 431 dot, melebihi 384 dot kertas 58mm
+		{name: "58mm uuid falls back to qr", paperWidth: 58, billId: testBillId, wantColumns: 32, wantQR: true},
+		{name: "58mm short id", paperWidth: 58, billId: "B-20230814-0001", wantColumns: 32},
+		{name: "80mm uuid", paperWidth: 80, billId: testBillId, wantColumns: 48},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill := newTestBill()
+			bill.Id = tt.billId
+			data, err := receipt.GenerateEscPos(bill, newTestShop(), tt.paperWidth)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var text []byte
+			doubleSize := false
+			for _, line := range bytes.Split(data, []byte("\n")) {
+				// data barcode dicetak sebagai gambar, bukan teks
+				if bytes.Contains(line, code128Command) || bytes.Contains(line, qrCommand) {
+					continue
+				}
+				// huruf ukuran ganda memakai dua kolom sampai ukuran dikembalikan normal
+				if index := bytes.LastIndex(line, []byte{0x1D, '!'}); index >= 0 && index+2 < len(line) {
+					doubleSize = line[index+2] == 0x11
+				}
+				line = escPosCommand.ReplaceAll(line, nil)
+				columns := len(line)
+				if doubleSize {
+					columns *= 2
+				}
+				if columns > tt.wantColumns {
+					t.Errorf("line %q is wider than %d columns", line, tt.wantColumns)
+				}
+				for _, b := range line {
+					if b < 0x20 || b > 0x7E {
+						t.Fatalf("line %q contains non printable byte %#x", line, b)
+					}
+				}
+				text = append(text, line...)
+				text = append(text, '\n')
+			}
+			for _, want := range []string{"TOTAL", "Rp 62.500", "Rp 42.500", "Cuci Kering Setrika ?kspres"} {
+				if !bytes.Contains(text, []byte(want)) {
+					t.Errorf("receipt should contain %q", want)
+				}
+			}
+
+			code128 := append(append(code128Command, byte(len(tt.billId)+2), '{', 'B'), tt.billId...)
+			if gotQR := bytes.Contains(data, qrCommand); gotQR != tt.wantQR {
+				t.Errorf("expected qr %v, got %v", tt.wantQR, gotQR)
+			}
+			if gotCode128 := bytes.Contains(data, code128); gotCode128 == tt.wantQR {
+				t.Errorf("expected code128 %v, got %v", !tt.wantQR, gotCode128)
+			}
+		})
+	}
+}
+
+func TestGenerateEscPosUnsupportedPaper(t *testing.T) {
+	if _, err := receipt.GenerateEscPos(newTestBill(), newTestShop(), 76); err == nil {
+		t.Error("76mm paper should be rejected")
+	}
+}
@@ -0,0 +1,25 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+)
+
+const dateLayout = "02 Jan 2006 15:04"
+
+// FormatRupiah memformat nominal menjadi "Rp 12.500"
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := fmt.Sprintf("%d", amount)
+	var groups []string
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	groups = append([]string{digits}, groups...)
+	return sign + "Rp " + strings.Join(groups, ".")
+}
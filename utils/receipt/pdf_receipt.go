@@ -0,0 +1,126 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+	"github.com/go-pdf/fpdf"
+)
+
+// GeneratePDF membuat nota bill dalam format PDF ukuran A5
+func GeneratePDF(bill dto.BillResponseDto, shop config.ShopConfig) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A5", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+	// font standar PDF hanya mendukung cp1252
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	// header toko
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(width, 7, tr(shop.ShopName), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{shop.ShopAddress, shop.ShopPhoneNumber} {
+		if line != "" {
+			pdf.CellFormat(width, 4.5, tr(line), "", 1, "C", false, 0, "")
+		}
+	}
+	pdf.Ln(2)
+	pdf.Line(left, pdf.GetY(), left+width, pdf.GetY())
+	pdf.Ln(3)
+
+	// informasi bill
+	info := [][2]string{
+		{"Bill ID", bill.Id},
+		{"Bill Date", bill.BillDate.Format("02 Jan 2006")},
+		{"Entry Date", bill.EntryDate.Format(dateLayout)},
+		{"Estimated Finish", bill.FinishDate.Format(dateLayout)},
+		{"Status", bill.Status},
+		{"Customer", bill.Customer.Name},
+		{"Phone", bill.Customer.PhoneNumber},
+		{"Address", bill.Customer.Address},
+		{"Served By", bill.Employee.Name},
+	}
+	pdf.SetFont("Helvetica", "", 9)
+	for _, item := range info {
+		pdf.CellFormat(32, 5, item[0], "", 0, "L", false, 0, "")
+		pdf.MultiCell(width-32, 5, ": "+tr(item[1]), "", "L", false)
+	}
+	pdf.Ln(3)
+
+	// detail item
+	columns := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"Item", width * 0.40, "L"},
+		{"Qty", width * 0.18, "R"},
+		{"Price", width * 0.20, "R"},
+		{"Subtotal", width * 0.22, "R"},
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	for _, column := range columns {
+		pdf.CellFormat(column.width, 6, column.title, "TB", 0, column.align, false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, detail := range bill.BillDetails {
+		values := []string{
+			fitText(pdf, tr(detail.Product.Name), columns[0].width),
+			tr(fmt.Sprintf("%d %s", detail.Qty, detail.Product.Uom.Name)),
+			FormatRupiah(detail.ProductPrice),
+			FormatRupiah(detail.ProductPrice * detail.Qty),
+		}
+		for i, column := range columns {
+			pdf.CellFormat(column.width, 5.5, values[i], "", 0, column.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Line(left, pdf.GetY(), left+width, pdf.GetY())
+	pdf.Ln(1)
+
+	// total
+	totals := [][2]string{
+		{"Total", FormatRupiah(bill.TotalBill)},
+		{"Paid", FormatRupiah(bill.AmountPaid)},
+		{"Balance Due", FormatRupiah(bill.BalanceDue)},
+	}
+	labelWidth := columns[0].width + columns[1].width + columns[2].width
+	for i, total := range totals {
+		style := ""
+		if i == 0 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(labelWidth, 5.5, total[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(columns[3].width, 5.5, total[1], "", 1, "R", false, 0, "")
+	}
+
+	// footer
+	if shop.ReceiptFooter != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.MultiCell(width, 4.5, tr(shop.ReceiptFooter), "", "C", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate pdf receipt: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitText memotong teks supaya tidak menabrak kolom di sebelahnya
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	for len(text) > 0 && pdf.GetStringWidth(text) > width-1 {
+		text = text[:len(text)-1]
+	}
+	return text
+}
@@ -0,0 +1,48 @@
+package receipt_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/NursiNursi/laundry-apps/utils/receipt"
+)
+
+var pdfStream = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// pdfContent menggabungkan isi semua stream pdf, fpdf mengompres stream dengan zlib
+func pdfContent(t *testing.T, data []byte) string {
+	t.Helper()
+	var content bytes.Buffer
+	for _, match := range pdfStream.FindAllSubmatch(data, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			content.Write(match[1])
+			continue
+		}
+		if _, err := io.Copy(&content, reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return content.String()
+}
+
+func TestGeneratePDF(t *testing.T) {
+	data, err := receipt.GeneratePDF(newTestBill(), newTestShop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.Contains(data[len(data)-16:], []byte("%%EOF")) {
+		t.Fatalf("output is not a complete pdf, got %d bytes", len(data))
+	}
+
+	content := pdfContent(t, data)
+	for _, want := range []string{testBillId, "Ani Lestari", "Bed Cover", "Rp 62.500", "Rp 42.500", "Terima kasih"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("pdf should contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package receipt_test
+
+import (
+	"time"
+
+	"github.com/NursiNursi/laundry-apps/config"
+	"github.com/NursiNursi/laundry-apps/model"
+	"github.com/NursiNursi/laundry-apps/model/dto"
+)
+
+// ID berbentuk uuid seperti yang dibuat common.GenerateID
+const testBillId = "0b9f3c2e-5d4a-4c1b-9e8f-7a6b5c4d3e2f"
+
+func newTestShop() config.ShopConfig {
+	return config.ShopConfig{
+		ShopName:        "Enigma Laundry Kiloan Bersih dan Wangi",
+		ShopAddress:     "Jl. Raya Ragunan No. 12, Pasar Minggu, Jakarta Selatan 12550",
+		ShopPhoneNumber: "0812-3456-7890",
+		ReceiptFooter:   "Terima kasih, cucian yang tidak diambil lebih dari 30 hari bukan tanggung jawab kami",
+	}
+}
+
+func newTestBill() dto.BillResponseDto {
+	entry := time.Date(2023, 8, 14, 9, 30, 0, 0, time.UTC)
+	kg := model.Uom{Id: "u1", Name: "Kg"}
+	return dto.BillResponseDto{
+		Id:         testBillId,
+		BillDate:   entry,
+		EntryDate:  entry,
+		FinishDate: entry.Add(48 * time.Hour),
+		Status:     model.BillStatusReceived,
+		Employee:   model.Employee{Id: "e1", Name: "Budi Santoso"},
+		Customer:   model.Customer{Id: "c1", Name: "Ani Lestari Wulandari Kusumaningrum", PhoneNumber: "081234567890", Address: "Jl. Melati 3"},
+		BillDetails: []dto.BillDetailResponseDto{
+			{Id: "d1", BillId: testBillId, Product: model.Product{Id: "p1", Name: "Cuci Kering Setrika Ékspres Satu Hari Selesai", Uom: kg}, ProductPrice: 12500, Qty: 3},
+			{Id: "d2", BillId: testBillId, Product: model.Product{Id: "p2", Name: "Bed Cover", Uom: model.Uom{Id: "u2", Name: "Pcs"}}, ProductPrice: 25000, Qty: 1},
+		},
+		TotalBill:     62500,
+		AmountPaid:    20000,
+		BalanceDue:    42500,
+		PaymentStatus: model.PaymentStatusPartiallyPaid,
+	}
+}
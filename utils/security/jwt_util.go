@@ -9,7 +9,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func CreateAccessToken(user model.UserCredential) (string, error) {
+func CreateAccessToken(user model.UserCredential, sessionId string) (string, error) {
 	cfg, _ := config.NewConfig()
 	now := time.Now().UTC()
 	end := now.Add(cfg.AccessTokenLifeTime)
@@ -20,9 +20,11 @@ func CreateAccessToken(user model.UserCredential) (string, error) {
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(end),
 		},
-		Username: user.Username,
-		// role
-		// services
+		Username:   user.Username,
+		Role:       user.Role,
+		Services:   ServicesForRole(user.Role),
+		SessionId:  sessionId,
+		EmployeeId: user.EmployeeId,
 	}
 
 	token := jwt.NewWithClaims(cfg.JwtSigningMethod, claims)
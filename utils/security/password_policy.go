@@ -0,0 +1,37 @@
+package security
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/NursiNursi/laundry-apps/config"
+)
+
+// ValidatePassword mengecek password terhadap aturan di PasswordConfig
+func ValidatePassword(password string, policy config.PasswordConfig) error {
+	if len([]rune(password)) < policy.PasswordMinLength {
+		return fmt.Errorf("password must be at least %d characters", policy.PasswordMinLength)
+	}
+
+	var hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if policy.PasswordRequireUpper && !hasUpper {
+		return fmt.Errorf("password must contain an uppercase letter")
+	}
+	if policy.PasswordRequireDigit && !hasDigit {
+		return fmt.Errorf("password must contain a digit")
+	}
+	if policy.PasswordRequireSymbol && !hasSymbol {
+		return fmt.Errorf("password must contain a symbol")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package security
+
+import "github.com/NursiNursi/laundry-apps/model"
+
+// daftar service (permission) yang di isi ke claims Services pada token
+const (
+	ServiceMasterWrite   = "master:write"
+	ServiceProductDelete = "product:delete"
+	ServiceCustomerWrite = "customer:write"
+	ServiceBillCreate    = "bill:create"
+	// membuat bill atas nama karyawan lain
+	ServiceBillAssign    = "bill:assign"
+	ServiceBillStatus    = "bill:status"
+	ServiceBillAmend     = "bill:amend"
+	ServiceBillVoid      = "bill:void"
+	ServicePaymentCreate = "payment:create"
+	ServiceUserManage    = "user:manage"
+	ServiceReportRead    = "report:read"
+	ServiceAuditRead     = "audit:read"
+	// melihat data yang sudah di soft delete
+	ServiceDeletedRead = "deleted:read"
+)
+
+var roleServices = map[string][]string{
+	model.RoleOwner: {
+		ServiceMasterWrite, ServiceProductDelete, ServiceCustomerWrite, ServiceBillCreate, ServiceBillAssign, ServiceBillStatus,
+		ServiceBillAmend, ServiceBillVoid, ServicePaymentCreate, ServiceUserManage, ServiceReportRead, ServiceAuditRead,
+		ServiceDeletedRead,
+	},
+	model.RoleAdmin: {
+		ServiceMasterWrite, ServiceCustomerWrite, ServiceBillCreate, ServiceBillAssign, ServiceBillStatus,
+		ServiceBillAmend, ServiceBillVoid, ServicePaymentCreate, ServiceUserManage, ServiceDeletedRead,
+	},
+	model.RoleCashier: {
+		ServiceCustomerWrite, ServiceBillCreate, ServiceBillStatus, ServiceBillAmend, ServicePaymentCreate,
+	},
+	model.RoleOperator: {
+		ServiceBillStatus,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleServices[role]
+	return ok
+}
+
+func ServicesForRole(role string) []string {
+	return roleServices[role]
+}
@@ -0,0 +1,69 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+type sessionCacheEntry struct {
+	userId    string
+	revoked   bool
+	expiresAt time.Time
+}
+
+// SessionCache menyimpan status session sementara supaya verifikasi token tidak selalu ke database
+type SessionCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]sessionCacheEntry
+}
+
+// Get mengembalikan status revoked, ok bernilai false kalau belum ada di cache atau sudah kadaluarsa
+func (s *SessionCache) Get(sessionId string) (revoked bool, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, found := s.entries[sessionId]
+	if !found || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.revoked, true
+}
+
+func (s *SessionCache) Set(sessionId string, userId string, revoked bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	// sekalian bersihkan entry yang sudah kadaluarsa
+	for id, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, id)
+		}
+	}
+	s.entries[sessionId] = sessionCacheEntry{userId: userId, revoked: revoked, expiresAt: now.Add(s.ttl)}
+}
+
+// Revoke langsung menandai session revoked tanpa menunggu ttl habis
+func (s *SessionCache) Revoke(sessionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.entries[sessionId]
+	entry.revoked = true
+	entry.expiresAt = time.Now().Add(s.ttl)
+	s.entries[sessionId] = entry
+}
+
+// RevokeUser menandai semua session milik user yang ada di cache
+func (s *SessionCache) RevokeUser(userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, entry := range s.entries {
+		if entry.userId == userId {
+			entry.revoked = true
+			s.entries[id] = entry
+		}
+	}
+}
+
+func NewSessionCache(ttl time.Duration) *SessionCache {
+	return &SessionCache{ttl: ttl, entries: make(map[string]sessionCacheEntry)}
+}
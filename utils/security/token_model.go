@@ -6,7 +6,10 @@ import (
 
 type TokenMyClaims struct {
 	jwt.RegisteredClaims
-	Username string `json:"username"`
-	Role     string
-	Services []string
+	Username string   `json:"username"`
+	Role     string   `json:"role"`
+	Services []string `json:"services"`
+	// id session di tabel user_session, dipakai untuk logout dan revoke
+	SessionId  string `json:"sid"`
+	EmployeeId string `json:"employeeId,omitempty"`
 }